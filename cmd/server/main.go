@@ -15,6 +15,7 @@ import (
 	"github.com/Fedasov/Effective-Mobile/internal/config"
 	"github.com/Fedasov/Effective-Mobile/internal/handler"
 	"github.com/Fedasov/Effective-Mobile/internal/middleware"
+	"github.com/Fedasov/Effective-Mobile/internal/model"
 	"github.com/Fedasov/Effective-Mobile/internal/repository"
 	"github.com/Fedasov/Effective-Mobile/internal/service"
 	"github.com/gorilla/mux"
@@ -34,17 +35,40 @@ import (
 func main() {
 	cfg := config.Load()
 
+	overlapPolicy := model.OverlapPolicy(cfg.OverlapPolicy)
+	if !overlapPolicy.Valid() {
+		log.Fatalf("Invalid OVERLAP_POLICY %q: must be reject, merge or allow", cfg.OverlapPolicy)
+	}
+
 	db, err := initDB(cfg)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer db.Close()
 
+	rateRepo := repository.NewRateRepository(db)
+	rateService := service.NewRateService(rateRepo)
+	rateHandler := handler.NewRateHandler(rateService)
+
+	if cfg.ExchangeRatesFile != "" {
+		if err := loadExchangeRates(rateService, cfg.ExchangeRatesFile); err != nil {
+			log.Fatalf("Failed to load exchange rates: %v", err)
+		}
+	}
+
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
-	subscriptionService := service.NewSubscriptionService(subscriptionRepo)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, rateRepo, service.Options{
+		IdempotencyTTL:   cfg.IdempotencyTTL,
+		OverlapPolicy:    overlapPolicy,
+		DeletedRetention: cfg.DeletedRetention,
+	})
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
 
-	router := setupRouter(subscriptionHandler)
+	catalogRepo := repository.NewCatalogRepository(db)
+	catalogService := service.NewCatalogService(catalogRepo)
+	catalogHandler := handler.NewCatalogHandler(catalogService)
+
+	router := setupRouter(subscriptionHandler, catalogHandler, rateHandler)
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.ServerPort,
@@ -58,11 +82,15 @@ func main() {
 		}
 	}()
 
+	maintenanceCtx, stopMaintenance := context.WithCancel(context.Background())
+	go runMaintenance(maintenanceCtx, subscriptionService)
+
 	// Ожидание сигнала для graceful shutdown
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("Shutting down server...")
+	stopMaintenance()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -74,6 +102,42 @@ func main() {
 	log.Println("Server exited")
 }
 
+// maintenanceInterval - период фоновой очистки устаревших данных
+const maintenanceInterval = time.Hour
+
+// runMaintenance периодически удаляет устаревшие данные, пока не будет отменен контекст
+func runMaintenance(ctx context.Context, subscriptionService service.SubscriptionService) {
+	ticker := time.NewTicker(maintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			subscriptionService.PurgeIdempotencyKeys()
+			subscriptionService.PurgeDeleted()
+		}
+	}
+}
+
+// loadExchangeRates загружает курсы валют из CSV-файла при запуске
+func loadExchangeRates(rateService service.RateService, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open exchange rates file: %w", err)
+	}
+	defer file.Close()
+
+	loaded, err := rateService.ImportCSV(file)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Loaded %d exchange rates from %s", loaded, path)
+	return nil
+}
+
 // initDB инициализирует подключение к PostgreSQL
 func initDB(cfg *config.Config) (*sql.DB, error) {
 	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
@@ -95,19 +159,52 @@ func initDB(cfg *config.Config) (*sql.DB, error) {
 	return db, nil
 }
 
-func setupRouter(subscriptionHandler *handler.SubscriptionHandler) *mux.Router {
+func setupRouter(subscriptionHandler *handler.SubscriptionHandler, catalogHandler *handler.CatalogHandler, rateHandler *handler.RateHandler) *mux.Router {
 	router := mux.NewRouter()
 
+	router.Use(middleware.RequestIDMiddleware)
 	router.Use(middleware.LoggingMiddleware)
+	router.NotFoundHandler = middleware.RequestIDMiddleware(http.HandlerFunc(handler.NotFound))
+	router.MethodNotAllowedHandler = middleware.RequestIDMiddleware(http.HandlerFunc(handler.MethodNotAllowed))
 
 	api := router.PathPrefix("/api/v1").Subrouter()
 
 	api.HandleFunc("/subscriptions", subscriptionHandler.Create).Methods("POST")
+	api.HandleFunc("/subscriptions/bulk", subscriptionHandler.BulkCreate).Methods("POST")
+	api.HandleFunc("/subscriptions/bulk", subscriptionHandler.BulkUpdate).Methods("PUT")
+	api.HandleFunc("/subscriptions/bulk/delete", subscriptionHandler.BulkDelete).Methods("POST")
+	api.HandleFunc("/subscriptions/import", subscriptionHandler.Import).Methods("POST")
+	api.HandleFunc("/subscriptions/export", subscriptionHandler.ExportList).Methods("GET")
 	api.HandleFunc("/subscriptions/{id}", subscriptionHandler.GetByID).Methods("GET")
 	api.HandleFunc("/subscriptions/{id}", subscriptionHandler.Update).Methods("PUT")
+	api.HandleFunc("/subscriptions/{id}", subscriptionHandler.Patch).Methods("PATCH")
 	api.HandleFunc("/subscriptions/{id}", subscriptionHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/subscriptions/{id}/restore", subscriptionHandler.Restore).Methods("POST")
+	api.HandleFunc("/subscriptions/{id}/history", subscriptionHandler.History).Methods("GET")
+	api.HandleFunc("/subscriptions/{id}/prices", subscriptionHandler.PriceSchedule).Methods("GET")
+	api.HandleFunc("/subscriptions/{id}/prices", subscriptionHandler.SchedulePriceChange).Methods("POST")
+	api.HandleFunc("/subscriptions/{id}/prices/{month}", subscriptionHandler.CancelPriceChange).Methods("DELETE")
+	api.HandleFunc("/subscriptions/{id}/promotions", subscriptionHandler.Promotions).Methods("GET")
+	api.HandleFunc("/subscriptions/{id}/promotions", subscriptionHandler.AddPromotion).Methods("POST")
+	api.HandleFunc("/subscriptions/{id}/promotions/{promotion_id}", subscriptionHandler.DeletePromotion).Methods("DELETE")
 	api.HandleFunc("/subscriptions", subscriptionHandler.List).Methods("GET")
 	api.HandleFunc("/subscriptions/total-cost", subscriptionHandler.GetTotalCost).Methods("POST")
+	api.HandleFunc("/subscriptions/total-cost/export", subscriptionHandler.ExportTotalCost).Methods("POST")
+	api.HandleFunc("/subscriptions/cost-breakdown", subscriptionHandler.GetCostBreakdown).Methods("POST")
+	api.HandleFunc("/subscriptions/cost-breakdown/export", subscriptionHandler.ExportCostBreakdown).Methods("POST")
+	api.HandleFunc("/subscriptions/spend-report", subscriptionHandler.GetSpendReport).Methods("POST")
+	api.HandleFunc("/users/{user_id}/subscriptions", subscriptionHandler.ListByUser).Methods("GET")
+
+	api.HandleFunc("/services", catalogHandler.Create).Methods("POST")
+	api.HandleFunc("/services", catalogHandler.List).Methods("GET")
+	api.HandleFunc("/services/{id}", catalogHandler.GetByID).Methods("GET")
+	api.HandleFunc("/services/{id}", catalogHandler.Update).Methods("PUT")
+	api.HandleFunc("/services/{id}", catalogHandler.Delete).Methods("DELETE")
+	api.HandleFunc("/services/{id}/merge", catalogHandler.Merge).Methods("POST")
+
+	api.HandleFunc("/exchange-rates", rateHandler.Upsert).Methods("PUT")
+	api.HandleFunc("/exchange-rates", rateHandler.List).Methods("GET")
+	api.HandleFunc("/exchange-rates/import", rateHandler.Import).Methods("POST")
 
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
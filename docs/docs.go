@@ -15,27 +15,21 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/subscriptions": {
+        "/api/v1/exchange-rates": {
             "get": {
-                "description": "Возвращает список подписок с поддержкой пагинации",
+                "description": "Возвращает загруженные курсы - стоимость единицы валюты в базовой валюте RUB. Курс RUB всегда равен 1 и не хранится",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "exchange-rates"
                 ],
-                "summary": "Получить список подписок",
+                "summary": "Получить курсы валют",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Лимит записей (по умолчанию 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Смещение (по умолчанию 0)",
-                        "name": "offset",
+                        "type": "string",
+                        "description": "Код валюты ISO 4217",
+                        "name": "currency",
                         "in": "query"
                     }
                 ],
@@ -45,23 +39,26 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/model.Subscription"
+                                "$ref": "#/definitions/model.ExchangeRate"
                             }
                         }
                     },
                     "500": {
                         "description": "Внутренняя ошибка сервера",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Создает новую запись о подписке пользователя",
+            "put": {
+                "description": "Сохраняет курсы валют по месяцам: rate - стоимость единицы валюты в рублях. Курс действует с месяца month\nдо следующего загруженного курса этой валюты. Курсы тех же месяцев заменяются; при ошибке в любом элементе ничего не сохраняется.\nRUB - базовая валюта с курсом 1: курсы RUB не принимаются и отклоняются с ошибкой поля currency",
                 "consumes": [
                     "application/json"
                 ],
@@ -69,42 +66,153 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "exchange-rates"
                 ],
-                "summary": "Создать новую подписку",
+                "summary": "Загрузить курсы валют",
                 "parameters": [
                     {
-                        "description": "Данные подписки",
+                        "description": "Курсы валют",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SubscriptionCreateRequest"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ExchangeRateRequest"
+                            }
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Subscription"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.ExchangeRate"
+                            }
                         }
                     },
                     "400": {
                         "description": "Неверный формат данных",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/subscriptions/total-cost": {
+        "/api/v1/exchange-rates/import": {
+            "post": {
+                "description": "Принимает CSV-файл с заголовком currency,month,rate (месяц в формате MM-YYYY) и сохраняет курсы так же, как PUT /exchange-rates.\nСтроки с базовой валютой RUB отклоняются",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "exchange-rates"
+                ],
+                "summary": "Импортировать курсы валют из CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV-файл с курсами",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ExchangeRateImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный файл",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Получить каталог сервисов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Категория сервиса",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Service"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Вычисляет общую стоимость подписок за указанный период с возможностью фильтрации",
+                "description": "Создает сервис с названием и синонимами. Названия сопоставляются без учета регистра и лишних пробелов",
                 "consumes": [
                     "application/json"
                 ],
@@ -112,56 +220,67 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "services"
                 ],
-                "summary": "Рассчитать общую стоимость",
+                "summary": "Добавить сервис в каталог",
                 "parameters": [
                     {
-                        "description": "Параметры расчета",
+                        "description": "Данные сервиса",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.TotalCostRequest"
+                            "$ref": "#/definitions/model.ServiceRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Общая стоимость",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "integer"
-                            }
+                            "$ref": "#/definitions/model.Service"
                         }
                     },
                     "400": {
-                        "description": "Неверные параметры",
+                        "description": "Неверный формат данных",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Название или синоним уже используется другим сервисом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/api/v1/subscriptions/{id}": {
+        "/api/v1/services/{id}": {
             "get": {
-                "description": "Возвращает информацию о подписке по её идентификатору",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "services"
                 ],
-                "summary": "Получить подписку по ID",
+                "summary": "Получить сервис по ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID подписки",
+                        "description": "ID сервиса",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -171,31 +290,37 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Subscription"
+                            "$ref": "#/definitions/model.Service"
                         }
                     },
                     "400": {
                         "description": "Неверный ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Подписка не найдена",
+                        "description": "Сервис не найден",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             },
             "put": {
-                "description": "Обновляет информацию о существующей подписке",
+                "description": "Перезаписывает сервис и его синонимы. При переименовании новое название записывается во все подписки сервиса",
                 "consumes": [
                     "application/json"
                 ],
@@ -203,64 +328,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "subscriptions"
+                    "services"
                 ],
-                "summary": "Обновить подписку",
+                "summary": "Обновить сервис",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID подписки",
+                        "description": "ID сервиса",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Новые данные подписки",
+                        "description": "Новые данные сервиса",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.SubscriptionCreateRequest"
+                            "$ref": "#/definitions/model.ServiceRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений подписок",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Subscription"
+                            "$ref": "#/definitions/model.Service"
                         }
                     },
                     "400": {
                         "description": "Неверные данные",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Подписка не найдена",
+                        "description": "Сервис не найден",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Название или синоним уже используется другим сервисом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Удаляет запись о подписке по её идентификатору",
+                "description": "Удаляет сервис из каталога, если на него не оформлено ни одной подписки",
                 "tags": [
-                    "subscriptions"
+                    "services"
                 ],
-                "summary": "Удалить подписку",
+                "summary": "Удалить сервис",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID подписки",
+                        "description": "ID сервиса",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -268,104 +411,3054 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Подписка успешно удалена"
+                        "description": "Сервис успешно удален"
                     },
                     "400": {
                         "description": "Неверный ID",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Подписка не найдена",
+                        "description": "Сервис не найден",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "На сервис оформлены подписки",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "model.Subscription": {
-            "type": "object",
-            "properties": {
-                "end_date": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer",
-                    "example": 1
-                },
-                "price": {
-                    "type": "integer",
-                    "example": 400
-                },
-                "service_name": {
-                    "type": "string",
-                    "example": "Yandex Plus"
-                },
-                "start_date": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string",
-                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
-                }
-            }
-        },
-        "model.SubscriptionCreateRequest": {
-            "type": "object",
-            "required": [
-                "price",
-                "service_name",
-                "start_date",
-                "user_id"
-            ],
-            "properties": {
-                "end_date": {
-                    "description": "Format: \"MM-YYYY\"",
-                    "type": "string",
-                    "example": "12-2025"
-                },
-                "price": {
-                    "type": "integer",
-                    "example": 400
-                },
-                "service_name": {
-                    "type": "string",
-                    "example": "Yandex Plus"
-                },
-                "start_date": {
-                    "description": "Format: \"MM-YYYY\"",
-                    "type": "string",
-                    "example": "07-2025"
-                },
-                "user_id": {
-                    "type": "string",
-                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
-                }
-            }
         },
-        "model.TotalCostRequest": {
-            "type": "object",
-            "required": [
-                "end_date",
-                "start_date"
-            ],
-            "properties": {
+        "/api/v1/services/{id}/merge": {
+            "post": {
+                "description": "Переносит подписки и синонимы сервиса source_id в сервис из пути и удаляет source_id.\nИспользуется, когда один сервис заведен под разными названиями.\nЕсли после переноса подписки пользователя пересекались бы по периоду с его подписками на оставшийся сервис,\nобъединение отклоняется с ошибкой 409, в сообщении перечислены ID пересекающихся подписок",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Объединить сервисы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID сервиса, который остается",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Объединяемый сервис",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ServiceMergeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений подписок",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сервис не найден",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписки пересекаются по периоду",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions": {
+            "get": {
+                "description": "Возвращает список подписок с поддержкой фильтрации, сортировки и пагинации.\nПо умолчанию используется пагинация смещением и ответ — массив подписок.\nПри передаче cursor (в том числе пустого) используется курсорная пагинация, а при cursor или with_total ответ оборачивается в конверт {items, next_cursor, total}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить список подписок",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Лимит записей (по умолчанию 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Смещение (по умолчанию 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название или синоним сервиса",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало названия сервиса без учета регистра",
+                        "name": "service_name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная цена в единицах валюты, десятичное число, например 199.99",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная цена в единицах валюты, десятичное число, например 199.99",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подписка активна на дату (YYYY-MM-DD или MM-YYYY)",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала не раньше (YYYY-MM-DD или MM-YYYY)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала не позже (YYYY-MM-DD или MM-YYYY)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата окончания не раньше (YYYY-MM-DD или MM-YYYY)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата окончания не позже (YYYY-MM-DD или MM-YYYY)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только подписки в пробном периоде на текущую дату",
+                        "name": "in_trial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только подписки, пробный период которых закончится в ближайшие N дней",
+                        "name": "trial_ends_within",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "service_name",
+                            "price",
+                            "user_id",
+                            "start_date",
+                            "end_date"
+                        ],
+                        "type": "string",
+                        "description": "Поле сортировки (по умолчанию id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление сортировки (по умолчанию asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы; пустое значение включает курсорную пагинацию с первой страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть общее количество записей",
+                        "name": "with_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включить удаленные подписки",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список подписок; при cursor или with_total — объект model.SubscriptionPage",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Subscription"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры фильтрации",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает новую запись о подписке пользователя.\nПересечение периода с подпиской того же пользователя на тот же сервис обрабатывается по политике OVERLAP_POLICY:\nreject — ошибка 409, merge — расширение периода существующей подписки, allow — создание с предупреждением в поле warnings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создать новую подписку",
+                "parameters": [
+                    {
+                        "description": "Данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SubscriptionCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Ключ идемпотентности: повтор запроса с тем же ключом возвращает исходную подписку",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true, если ответ возвращен по ранее использованному ключу"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт с существующими данными или ключ идемпотентности использован с другим запросом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение данных",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/bulk": {
+            "put": {
+                "description": "Перезаписывает до 1000 подписок за один запрос. Поле version элемента работает как If-Match для этой подписки.\nПересечение периодов обрабатывается по политике OVERLAP_POLICY, как при обновлении одной подписки.\nРежимы atomic и best_effort работают так же, как при пакетном создании",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Обновить подписки пакетом",
+                "parameters": [
+                    {
+                        "description": "Подписки для обновления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkUpdateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных, режим или размер пакета",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создает до 1000 подписок за один запрос. Каждый элемент проверяется отдельно, результаты возвращаются по индексам элементов.\nВ режиме atomic (по умолчанию) при ошибке любого элемента не сохраняется ни один, остальные элементы получают статус 424.\nВ режиме best_effort сохраняются все элементы без ошибок",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Создать подписки пакетом",
+                "parameters": [
+                    {
+                        "description": "Подписки для создания",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkCreateRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных, режим или размер пакета",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/bulk/delete": {
+            "post": {
+                "description": "Помечает удаленными до 1000 подписок по списку ID.\nРежимы atomic и best_effort работают так же, как при пакетном создании",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить подписки пакетом",
+                "parameters": [
+                    {
+                        "description": "ID подписок для удаления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkDeleteRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных, режим или размер пакета",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/cost-breakdown": {
+            "post": {
+                "description": "Возвращает стоимость подписок по каждому месяцу периода с возможностью группировки по сервису или пользователю.\nПериод не длиннее 120 месяцев",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Помесячная разбивка стоимости",
+                "parameters": [
+                    {
+                        "description": "Параметры расчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CostBreakdownRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.CostBreakdownResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Нет курса валюты за месяц периода или сумма превышает допустимую",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/cost-breakdown/export": {
+            "post": {
+                "description": "Рассчитывает помесячную стоимость так же, как /subscriptions/cost-breakdown, и возвращает ее таблицей CSV или XLSX.\nПри группировке каждая группа месяца выгружается отдельной строкой; последняя строка содержит итог за период.\nКолонка currency содержит валюту отчета, в которую пересчитаны суммы",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Выгрузить помесячную стоимость подписок",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Параметры расчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CostBreakdownRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Формат не поддерживается",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/export": {
+            "get": {
+                "description": "Выгружает все подписки, подходящие под фильтры списка, в CSV или XLSX. Пагинация не применяется, строки передаются потоком.\nФормат выбирается параметром format, а если он не задан - заголовком Accept (text/csv или\napplication/vnd.openxmlformats-officedocument.spreadsheetml.sheet), по умолчанию CSV",
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Выгрузить список подписок",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название или синоним сервиса",
+                        "name": "service_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало названия сервиса без учета регистра",
+                        "name": "service_name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Минимальная цена в единицах валюты, десятичное число, например 199.99",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Максимальная цена в единицах валюты, десятичное число, например 199.99",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подписка активна на дату (YYYY-MM-DD или MM-YYYY)",
+                        "name": "active_at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала не раньше (YYYY-MM-DD или MM-YYYY)",
+                        "name": "start_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата начала не позже (YYYY-MM-DD или MM-YYYY)",
+                        "name": "start_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата окончания не раньше (YYYY-MM-DD или MM-YYYY)",
+                        "name": "end_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата окончания не позже (YYYY-MM-DD или MM-YYYY)",
+                        "name": "end_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только подписки в пробном периоде на текущую дату",
+                        "name": "in_trial",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только подписки, пробный период которых закончится в ближайшие N дней",
+                        "name": "trial_ends_within",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "service_name",
+                            "price",
+                            "user_id",
+                            "start_date",
+                            "end_date"
+                        ],
+                        "type": "string",
+                        "description": "Поле сортировки (по умолчанию id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Направление сортировки (по умолчанию asc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Включить удаленные подписки",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры фильтрации",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Формат не поддерживается",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/import": {
+            "post": {
+                "description": "Принимает CSV-файл с заголовком и колонками service_name, price, currency, billing_period, billing_interval, anchor_day, trial_days, user_id, start_date, end_date (даты в формате YYYY-MM-DD или MM-YYYY; обязательны service_name, price, user_id и start_date).\nПри dry_run=true строки только проверяются без сохранения, и возвращаются ошибки каждой строки.\nИначе корректные строки сохраняются в режиме mode так же, как при пакетном создании",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Импортировать подписки из CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV-файл с подписками",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Только проверить файл без сохранения",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "atomic",
+                            "best_effort"
+                        ],
+                        "type": "string",
+                        "description": "Режим сохранения (по умолчанию atomic)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный файл или параметры",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/spend-report": {
+            "post": {
+                "description": "Возвращает общую стоимость подписок за период, сгруппированную по сервису, пользователю или обоим полям, с сортировкой и ограничением top-N",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Отчет о расходах по сервисам и пользователям",
+                "parameters": [
+                    {
+                        "description": "Параметры отчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SpendReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SpendReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Нет курса валюты за месяц периода или сумма превышает допустимую",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/total-cost": {
+            "post": {
+                "description": "Вычисляет общую стоимость подписок за указанный период с учетом количества оплачиваемых месяцев каждой подписки.\nЦены в других валютах пересчитываются в currency по курсу каждого месяца; by_currency содержит суммы без пересчета",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Рассчитать общую стоимость",
+                "parameters": [
+                    {
+                        "description": "Параметры расчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TotalCostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Общая стоимость",
+                        "schema": {
+                            "$ref": "#/definitions/model.TotalCostResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Нет курса валюты за месяц периода или сумма превышает допустимую",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/total-cost/export": {
+            "post": {
+                "description": "Рассчитывает общую стоимость так же, как /subscriptions/total-cost, и возвращает ее таблицей CSV или XLSX",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+                    "application/json"
+                ],
+                "tags": [
+                    "export"
+                ],
+                "summary": "Выгрузить общую стоимость подписок",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "Формат выгрузки",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Параметры расчета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TotalCostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Файл выгрузки",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "406": {
+                        "description": "Формат не поддерживается",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}": {
+            "get": {
+                "description": "Возвращает информацию о подписке по её идентификатору",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить подписку по ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Вернуть подписку, даже если она удалена",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Версия подписки"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Обновляет информацию о существующей подписке.\nПересечение периода с другой подпиской того же пользователя на тот же сервис обрабатывается по политике OVERLAP_POLICY:\nreject — ошибка 409, merge — поглощение пересекающейся подписки с расширением периода, allow — изменение с предупреждением в поле warnings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Обновить подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Новые данные подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SubscriptionCreateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт с существующими данными",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение данных",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Помечает подписку удаленной. Удаленная подписка не попадает в выборки и отчеты, ее можно восстановить до окончательной очистки",
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Подписка успешно удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Обновляет только переданные поля подписки по семантике JSON Merge Patch. Передача end_date: null отменяет дату окончания.\nПересечение периода с другими подписками обрабатывается по политике OVERLAP_POLICY, как при полном обновлении",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Частично обновить подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Изменяемые поля подписки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SubscriptionPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Конфликт с существующими данными",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Неподдерживаемый тип содержимого",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Нарушено ограничение данных",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/history": {
+            "get": {
+                "description": "Возвращает изменения подписки в порядке их выполнения со снимками до и после изменения,\nавтором из заголовка X-Actor и ID запроса. История доступна и для удаленных подписок",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить историю изменений подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.AuditEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/prices": {
+            "get": {
+                "description": "Возвращает базовую цену с месяца начала подписки и последующие изменения цены по возрастанию месяца.\nКаждая цена действует с месяца effective_from до следующего изменения",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить график цен подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PriceChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Устанавливает цену, действующую с месяца effective_from (не раньше следующего месяца) до следующего изменения.\nПовторное изменение с тем же месяцем заменяет цену. Стоимость прошедших и текущего месяцев не меняется",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Запланировать изменение цены подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Месяц и новая цена",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PriceChangeRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.PriceChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/prices/{month}": {
+            "delete": {
+                "description": "Удаляет изменение цены с указанного месяца. Отменить можно только изменение, которое начинается в будущем месяце",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Отменить изменение цены подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Месяц изменения (MM-YYYY)",
+                        "name": "month",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Изменение цены отменено"
+                    },
+                    "400": {
+                        "description": "Неверный ID или месяц",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Изменение цены не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/promotions": {
+            "get": {
+                "description": "Возвращает акции подписки по возрастанию даты начала. Во время акции списания идут по цене акции вместо обычной",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Получить промо-цены подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Promotion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Устанавливает цену акции (0 - бесплатно) с start_date по end_date включительно. Акция начинается в периоде подписки,\nне раньше следующего месяца, чтобы не менять стоимость уже оплаченных периодов,\nи не пересекается с другими акциями. В пробном периоде подписка бесплатна независимо от акций",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Добавить промо-цену подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Период и цена акции",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PromotionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный формат данных",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Период пересекается с другой акцией",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/promotions/{promotion_id}": {
+            "delete": {
+                "description": "Удаляет акцию; списания в ее период снова идут по обычной цене. Удалить можно только акцию, которая начинается в будущем месяце",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Удалить промо-цену подписки",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID акции",
+                        "name": "promotion_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag подписки, полученный ранее; при несовпадении версии возвращается 412",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Акция удалена"
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Акция не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Подписка была изменена другим клиентом",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/subscriptions/{id}/restore": {
+            "post": {
+                "description": "Снимает отметку об удалении с подписки, если она еще не была окончательно очищена.\nПересечение периода с подписками того же пользователя на тот же сервис обрабатывается по политике OVERLAP_POLICY, как при обновлении",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Восстановить подписку",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID подписки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Автор изменения для журнала изменений",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Подписка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Подписка не удалена или пересекается с другой подпиской",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/users/{user_id}/subscriptions": {
+            "get": {
+                "description": "Возвращает все подписки пользователя со статусом (active, expired, upcoming) и текущими ежемесячными расходами",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Получить подписки пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.UserSubscriptionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный ID пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Хранилище временно недоступно",
+                        "schema": {
+                            "$ref": "#/definitions/model.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "model.AuditAction": {
+            "type": "string",
+            "enum": [
+                "create",
+                "update",
+                "patch",
+                "delete",
+                "restore",
+                "merge",
+                "catalog",
+                "price",
+                "promotion"
+            ],
+            "x-enum-varnames": [
+                "AuditCreate",
+                "AuditUpdate",
+                "AuditPatch",
+                "AuditDelete",
+                "AuditRestore",
+                "AuditMerge",
+                "AuditCatalog",
+                "AuditPrice",
+                "AuditPromotion"
+            ]
+        },
+        "model.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/model.AuditAction"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "subscription_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkCreateRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SubscriptionCreateRequest"
+                    }
+                },
+                "mode": {
+                    "description": "Mode - atomic (по умолчанию) или best_effort",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ],
+                    "example": "atomic"
+                }
+            }
+        },
+        "model.BulkDeleteRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                },
+                "mode": {
+                    "description": "Mode - atomic (по умолчанию) или best_effort",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ],
+                    "example": "best_effort"
+                }
+            }
+        },
+        "model.BulkItemResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/model.ErrorResponse"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "index": {
+                    "description": "Index - позиция элемента в запросе",
+                    "type": "integer",
+                    "example": 0
+                },
+                "line": {
+                    "description": "Line - номер строки CSV-файла, заполняется при импорте",
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "description": "Status - HTTP-статус, который получил бы элемент при отдельном запросе",
+                    "type": "integer",
+                    "example": 201
+                },
+                "subscription": {
+                    "$ref": "#/definitions/model.Subscription"
+                }
+            }
+        },
+        "model.BulkMode": {
+            "type": "string",
+            "enum": [
+                "atomic",
+                "best_effort"
+            ],
+            "x-enum-varnames": [
+                "BulkAtomic",
+                "BulkBestEffort"
+            ]
+        },
+        "model.BulkResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "Committed - сохранены ли изменения; в режиме atomic false, если хотя бы один элемент завершился ошибкой",
+                    "type": "boolean",
+                    "example": true
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ],
+                    "example": "atomic"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.BulkUpdateItem": {
+            "type": "object",
+            "required": [
+                "price",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "anchor_day": {
+                    "description": "AnchorDay - день списания, по умолчанию день даты начала. Первое списание приходится на дату начала,\nследующие - на этот день каждого периода",
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1,
+                    "example": 17
+                },
+                "billing_interval": {
+                    "description": "BillingInterval - количество месяцев в периоде, задается только для billing_period=custom",
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1,
+                    "example": 6
+                },
+                "billing_period": {
+                    "description": "BillingPeriod - периодичность списаний, по умолчанию monthly; price - цена одного периода",
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "quarterly",
+                        "yearly",
+                        "custom"
+                    ],
+                    "example": "monthly"
+                },
+                "currency": {
+                    "description": "Currency - код валюты ISO 4217; по умолчанию валюта сервиса из каталога или RUB",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\" - по последнее число месяца включительно",
+                    "type": "string",
+                    "example": "2025-12-16"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "description": "Цена в единицах валюты с точностью до сотых: строка \"399.90\" или число",
+                    "type": "string",
+                    "maxLength": 100000000,
+                    "example": "399.90"
+                },
+                "service_id": {
+                    "description": "ServiceID - сервис из каталога; если задан, service_name не учитывается",
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "description": "ServiceName - название или синоним сервиса; неизвестное название добавляется в каталог",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\" - с первого числа месяца",
+                    "type": "string",
+                    "example": "2025-07-17"
+                },
+                "trial_days": {
+                    "description": "TrialDays - длина бесплатного пробного периода в днях; списания в нем не оплачиваются",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 14
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                },
+                "version": {
+                    "description": "Version - ожидаемая версия подписки, аналог заголовка If-Match",
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "model.BulkUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkUpdateItem"
+                    }
+                },
+                "mode": {
+                    "description": "Mode - atomic (по умолчанию) или best_effort",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ],
+                    "example": "atomic"
+                }
+            }
+        },
+        "model.CostBreakdownRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Валюта результата (ISO 4217), по умолчанию RUB. Цены в других валютах пересчитываются по курсу каждого месяца",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "12-2025"
+                },
+                "group_by": {
+                    "description": "Дополнительная группировка внутри месяца: \"service_name\" или \"user_id\"",
+                    "type": "string",
+                    "enum": [
+                        "service_name",
+                        "user_id"
+                    ],
+                    "example": "service_name"
+                },
+                "proration": {
+                    "description": "Proration - неполные месяцы подписки оплачиваются пропорционально дням использования, а не целиком",
+                    "type": "boolean",
+                    "example": false
+                },
+                "service_name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2025"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "model.CostBreakdownResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "months": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MonthlyCost"
+                    }
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "4800.00"
+                }
+            }
+        },
+        "model.CurrencyTotal": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "months": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "120.00"
+                }
+            }
+        },
+        "model.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "validation_error"
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.FieldError"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "validation failed: start_date: must be a month in MM-YYYY format"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "3f2b8c1e-6a4d-4e0f-9b7a-2d5c8e1f0a93"
+                }
+            }
+        },
+        "model.ExchangeRate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number",
+                    "example": 92.5
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ExchangeRateImportResponse": {
+            "type": "object",
+            "properties": {
+                "loaded": {
+                    "description": "Количество загруженных курсов",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "model.ExchangeRateRequest": {
+            "type": "object",
+            "required": [
+                "currency",
+                "month",
+                "rate"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Код валюты ISO 4217, кроме базовой валюты RUB",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "month": {
+                    "description": "Format: \"MM-YYYY\"",
+                    "type": "string",
+                    "example": "01-2025"
+                },
+                "rate": {
+                    "description": "Стоимость единицы валюты в рублях, не более 10 знаков после точки",
+                    "type": "number",
+                    "maximum": 100000000,
+                    "example": 92.5
+                }
+            }
+        },
+        "model.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "start_date"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be a month in MM-YYYY format"
+                }
+            }
+        },
+        "model.GroupCost": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "400.00"
+                }
+            }
+        },
+        "model.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "description": "Committed - сохранены ли изменения; в режиме atomic false, если хотя бы один элемент завершился ошибкой",
+                    "type": "boolean",
+                    "example": true
+                },
+                "dry_run": {
+                    "description": "DryRun - файл только проверен, подписки не сохранялись",
+                    "type": "boolean",
+                    "example": false
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.BulkMode"
+                        }
+                    ],
+                    "example": "atomic"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkItemResult"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.MonthlyCost": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GroupCost"
+                    }
+                },
+                "month": {
+                    "description": "Format: \"YYYY-MM\"",
+                    "type": "string",
+                    "example": "2025-01"
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "1200.00"
+                }
+            }
+        },
+        "model.PriceChange": {
+            "type": "object",
+            "properties": {
+                "effective_from": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "500.00"
+                }
+            }
+        },
+        "model.PriceChangeRequest": {
+            "type": "object",
+            "required": [
+                "effective_from",
+                "price"
+            ],
+            "properties": {
+                "effective_from": {
+                    "description": "Format: \"MM-YYYY\", не раньше следующего месяца",
+                    "type": "string",
+                    "example": "03-2026"
+                },
+                "price": {
+                    "type": "string",
+                    "maxLength": 100000000,
+                    "example": "499.90"
+                }
+            }
+        },
+        "model.Promotion": {
+            "type": "object",
+            "properties": {
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "description": "Цена за период списания во время акции; 0 - бесплатно",
+                    "type": "string",
+                    "example": "199.00"
+                },
+                "start_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PromotionRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "end_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\" - по последнее число месяца включительно",
+                    "type": "string",
+                    "example": "2025-09-30"
+                },
+                "price": {
+                    "type": "string",
+                    "maxLength": 100000000,
+                    "minLength": 0,
+                    "example": "199.00"
+                },
+                "start_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\" - с первого числа месяца; не раньше следующего месяца",
+                    "type": "string",
+                    "example": "2025-07-01"
+                }
+            }
+        },
+        "model.Service": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Aliases - другие написания названия, которые сопоставляются с этим сервисом",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Яндекс Плюс"
+                    ]
+                },
+                "category": {
+                    "type": "string",
+                    "example": "music"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "Currency - код валюты ISO 4217",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "default_price": {
+                    "type": "string",
+                    "example": "400.00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ServiceMergeRequest": {
+            "type": "object",
+            "required": [
+                "source_id"
+            ],
+            "properties": {
+                "source_id": {
+                    "description": "SourceID - сервис, который объединяется с текущим и удаляется",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "model.ServiceRequest": {
+            "type": "object",
+            "required": [
+                "aliases",
+                "name"
+            ],
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Яндекс Плюс"
+                    ]
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "music"
+                },
+                "currency": {
+                    "description": "Код валюты ISO 4217",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "default_price": {
+                    "type": "string",
+                    "maxLength": 100000000,
+                    "example": "399.90"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Yandex Plus"
+                }
+            }
+        },
+        "model.SpendGroup": {
+            "type": "object",
+            "properties": {
+                "months": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "4800.00"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "model.SpendReportRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "group_by",
+                "start_date"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Валюта результата (ISO 4217), по умолчанию RUB. Цены в других валютах пересчитываются по курсу каждого месяца",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "type": "string",
+                    "example": "12-2025"
+                },
+                "group_by": {
+                    "description": "Поля группировки: \"service_name\", \"user_id\" или оба",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "service_name"
+                    ]
+                },
+                "limit": {
+                    "description": "Количество групп в ответе (top-N), без ограничения если не задано",
+                    "type": "integer",
+                    "example": 5
+                },
+                "order": {
+                    "description": "Направление сортировки: \"desc\" (по умолчанию) или \"asc\"",
+                    "type": "string",
+                    "enum": [
+                        "asc",
+                        "desc"
+                    ],
+                    "example": "desc"
+                },
+                "proration": {
+                    "description": "Proration - неполные месяцы подписки оплачиваются пропорционально дням использования, а не целиком",
+                    "type": "boolean",
+                    "example": false
+                },
+                "service_name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Yandex Plus"
+                },
+                "sort_by": {
+                    "description": "Поле сортировки: \"total_cost\" (по умолчанию) или \"key\"",
+                    "type": "string",
+                    "enum": [
+                        "total_cost",
+                        "key"
+                    ],
+                    "example": "total_cost"
+                },
+                "start_date": {
+                    "type": "string",
+                    "example": "01-2025"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "model.SpendReportResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SpendGroup"
+                    }
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "4800.00"
+                }
+            }
+        },
+        "model.Subscription": {
+            "type": "object",
+            "properties": {
+                "anchor_day": {
+                    "description": "День списания: число месяца (в коротких месяцах - последний день), для weekly - день недели (1 - понедельник)",
+                    "type": "integer",
+                    "example": 17
+                },
+                "billing_interval": {
+                    "description": "Количество месяцев (для weekly - недель) в периоде списания",
+                    "type": "integer",
+                    "example": 1
+                },
+                "billing_period": {
+                    "description": "Периодичность списаний; цена указана за один период",
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "quarterly",
+                        "yearly",
+                        "custom"
+                    ],
+                    "example": "monthly"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "deleted_at": {
+                    "description": "Время мягкого удаления; заполнено только у удаленных подписок",
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "type": "string",
+                    "example": "400.00"
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "description": "Количество бесплатных дней пробного периода с даты начала",
+                    "type": "integer",
+                    "example": 14
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                },
+                "version": {
+                    "description": "Версия записи, увеличивается при каждом изменении и используется в ETag",
+                    "type": "integer",
+                    "example": 1
+                },
+                "warnings": {
+                    "description": "Предупреждения, возникшие при создании (например, пересечение с другой подпиской)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SubscriptionCreateRequest": {
+            "type": "object",
+            "required": [
+                "price",
+                "start_date",
+                "user_id"
+            ],
+            "properties": {
+                "anchor_day": {
+                    "description": "AnchorDay - день списания, по умолчанию день даты начала. Первое списание приходится на дату начала,\nследующие - на этот день каждого периода",
+                    "type": "integer",
+                    "maximum": 31,
+                    "minimum": 1,
+                    "example": 17
+                },
+                "billing_interval": {
+                    "description": "BillingInterval - количество месяцев в периоде, задается только для billing_period=custom",
+                    "type": "integer",
+                    "maximum": 120,
+                    "minimum": 1,
+                    "example": 6
+                },
+                "billing_period": {
+                    "description": "BillingPeriod - периодичность списаний, по умолчанию monthly; price - цена одного периода",
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "quarterly",
+                        "yearly",
+                        "custom"
+                    ],
+                    "example": "monthly"
+                },
+                "currency": {
+                    "description": "Currency - код валюты ISO 4217; по умолчанию валюта сервиса из каталога или RUB",
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\" - по последнее число месяца включительно",
+                    "type": "string",
+                    "example": "2025-12-16"
+                },
+                "price": {
+                    "description": "Цена в единицах валюты с точностью до сотых: строка \"399.90\" или число",
+                    "type": "string",
+                    "maxLength": 100000000,
+                    "example": "399.90"
+                },
+                "service_id": {
+                    "description": "ServiceID - сервис из каталога; если задан, service_name не учитывается",
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "description": "ServiceName - название или синоним сервиса; неизвестное название добавляется в каталог",
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\" - с первого числа месяца",
+                    "type": "string",
+                    "example": "2025-07-17"
+                },
+                "trial_days": {
+                    "description": "TrialDays - длина бесплатного пробного периода в днях; списания в нем не оплачиваются",
+                    "type": "integer",
+                    "maximum": 365,
+                    "minimum": 0,
+                    "example": 14
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "model.SubscriptionPatchRequest": {
+            "type": "object",
+            "properties": {
+                "anchor_day": {
+                    "description": "null возвращает день списания по умолчанию - день даты начала",
+                    "type": "integer",
+                    "example": 17
+                },
+                "billing_interval": {
+                    "description": "Количество месяцев в периоде для billing_period=custom",
+                    "type": "integer",
+                    "example": 6
+                },
+                "billing_period": {
+                    "description": "Изменение периодичности сбрасывает интервал и день списания, если они не переданы вместе с ней",
+                    "type": "string",
+                    "example": "yearly"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "end_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\", null отменяет дату окончания",
+                    "type": "string",
+                    "example": "2025-12-16"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "399.90"
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "description": "Format: \"YYYY-MM-DD\" или \"MM-YYYY\"",
+                    "type": "string",
+                    "example": "2025-07-17"
+                },
+                "trial_days": {
+                    "type": "integer",
+                    "example": 14
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
+        },
+        "model.TotalCostRequest": {
+            "type": "object",
+            "required": [
+                "end_date",
+                "start_date"
+            ],
+            "properties": {
+                "currency": {
+                    "description": "Валюта результата (ISO 4217), по умолчанию RUB. Цены в других валютах пересчитываются по курсу каждого месяца",
+                    "type": "string",
+                    "example": "RUB"
+                },
                 "end_date": {
                     "type": "string",
                     "example": "12-2025"
                 },
+                "proration": {
+                    "description": "Proration - неполные месяцы подписки оплачиваются пропорционально дням использования, а не целиком",
+                    "type": "boolean",
+                    "example": false
+                },
                 "service_name": {
                     "type": "string",
+                    "maxLength": 255,
                     "example": "Yandex Plus"
                 },
                 "start_date": {
@@ -377,6 +3470,145 @@ const docTemplate = `{
                     "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
                 }
             }
+        },
+        "model.TotalCostResponse": {
+            "type": "object",
+            "properties": {
+                "by_currency": {
+                    "description": "Суммы в исходных валютах подписок без пересчета",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.CurrencyTotal"
+                    }
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "months": {
+                    "description": "Суммарное количество оплачиваемых месяцев по всем подпискам периода",
+                    "type": "integer",
+                    "example": 12
+                },
+                "total_cost": {
+                    "type": "string",
+                    "example": "4800.00"
+                }
+            }
+        },
+        "model.UserSubscription": {
+            "type": "object",
+            "properties": {
+                "anchor_day": {
+                    "description": "День списания: число месяца (в коротких месяцах - последний день), для weekly - день недели (1 - понедельник)",
+                    "type": "integer",
+                    "example": 17
+                },
+                "billing_interval": {
+                    "description": "Количество месяцев (для weekly - недель) в периоде списания",
+                    "type": "integer",
+                    "example": 1
+                },
+                "billing_period": {
+                    "description": "Периодичность списаний; цена указана за один период",
+                    "type": "string",
+                    "enum": [
+                        "weekly",
+                        "monthly",
+                        "quarterly",
+                        "yearly",
+                        "custom"
+                    ],
+                    "example": "monthly"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "deleted_at": {
+                    "description": "Время мягкого удаления; заполнено только у удаленных подписок",
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "price": {
+                    "type": "string",
+                    "example": "400.00"
+                },
+                "service_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "service_name": {
+                    "type": "string",
+                    "example": "Yandex Plus"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "expired",
+                        "upcoming"
+                    ],
+                    "example": "active"
+                },
+                "trial_days": {
+                    "description": "Количество бесплатных дней пробного периода с даты начала",
+                    "type": "integer",
+                    "example": 14
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                },
+                "version": {
+                    "description": "Версия записи, увеличивается при каждом изменении и используется в ETag",
+                    "type": "integer",
+                    "example": 1
+                },
+                "warnings": {
+                    "description": "Предупреждения, возникшие при создании (например, пересечение с другой подпиской)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.UserSubscriptionsResponse": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "RUB"
+                },
+                "monthly_spend": {
+                    "description": "Месячные расходы на активные подписки в валюте Currency; цены других периодичностей приводятся к месяцу",
+                    "type": "string",
+                    "example": "1200.00"
+                },
+                "subscriptions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.UserSubscription"
+                    }
+                },
+                "user_id": {
+                    "type": "string",
+                    "example": "60601fee-2bf1-4721-ae6f-7636e79a0cba"
+                }
+            }
         }
     }
 }`
@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -14,6 +15,14 @@ type Config struct {
 	DBPassword string
 	DBName     string
 	ServerPort string
+	// IdempotencyTTL - срок хранения ключей идемпотентности
+	IdempotencyTTL time.Duration
+	// OverlapPolicy - поведение при пересечении подписок: reject, merge или allow
+	OverlapPolicy string
+	// DeletedRetention - срок хранения мягко удаленных подписок
+	DeletedRetention time.Duration
+	// ExchangeRatesFile - CSV-файл с курсами валют, загружаемый при запуске
+	ExchangeRatesFile string
 }
 
 func Load() *Config {
@@ -28,6 +37,13 @@ func Load() *Config {
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "subscriptions"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+
+		IdempotencyTTL: getDurationEnv("IDEMPOTENCY_TTL", 24*time.Hour),
+		OverlapPolicy:  getEnv("OVERLAP_POLICY", "reject"),
+
+		DeletedRetention: getDurationEnv("DELETED_RETENTION", 30*24*time.Hour),
+
+		ExchangeRatesFile: os.Getenv("EXCHANGE_RATES_FILE"),
 	}
 }
 
@@ -39,3 +55,18 @@ func getEnv(key, defaultValue string) string {
 
 	return value
 }
+
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration in %s: %v, using default %s", key, err, defaultValue)
+		return defaultValue
+	}
+
+	return duration
+}
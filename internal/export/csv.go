@@ -0,0 +1,33 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = formatCell(cell)
+		// Текст, похожий на формулу, дополняется апострофом, и редактор показывает его как текст
+		if _, isText := cell.(string); isText && looksLikeFormula(record[i]) {
+			record[i] = "'" + record[i]
+		}
+	}
+
+	// csv.Writer буферизует вывод и сам сбрасывает его в поток по мере заполнения буфера
+	return c.w.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,101 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// Format - формат табличной выгрузки
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// Типы содержимого поддерживаемых форматов
+const (
+	ContentTypeCSV  = "text/csv; charset=utf-8"
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// Writer построчно записывает таблицу в выходной поток. Значения ячеек - строки или целые числа.
+// Close дописывает окончание файла и должен вызываться после последней строки
+type Writer interface {
+	WriteRow(cells ...interface{}) error
+	Close() error
+}
+
+// NewWriter создает Writer указанного формата. sheet - имя листа для форматов, где оно есть
+func NewWriter(format Format, w io.Writer, sheet string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheet)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return ContentTypeXLSX
+	}
+
+	return ContentTypeCSV
+}
+
+// Negotiate выбирает формат по параметру format, а если он не задан - по заголовку Accept.
+// Без явных предпочтений используется CSV. ok = false, если ни один запрошенный формат не поддерживается
+func Negotiate(format, accept string) (Format, bool) {
+	if format != "" {
+		switch Format(strings.ToLower(format)) {
+		case FormatCSV:
+			return FormatCSV, true
+		case FormatXLSX:
+			return FormatXLSX, true
+		}
+		return "", false
+	}
+
+	if strings.TrimSpace(accept) == "" {
+		return FormatCSV, true
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		switch mediaType {
+		case "text/csv", "*/*", "text/*":
+			return FormatCSV, true
+		case ContentTypeXLSX:
+			return FormatXLSX, true
+		}
+	}
+
+	return "", false
+}
+
+// formatCell приводит значение ячейки к строке
+func formatCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// looksLikeFormula сообщает, что табличный редактор может выполнить текстовое значение как формулу:
+// оно начинается с =, +, - или @, а также с табуляции или возврата каретки
+func looksLikeFormula(value string) bool {
+	return value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0]))
+}
@@ -0,0 +1,20 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/middleware"
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// actorHeader - заголовок с идентификатором пользователя или системы, выполняющей изменение
+const actorHeader = "X-Actor"
+
+// auditMeta собирает сведения об источнике изменения для журнала
+func auditMeta(r *http.Request) model.AuditMeta {
+	return model.AuditMeta{
+		Actor:     strings.TrimSpace(r.Header.Get(actorHeader)),
+		RequestID: middleware.GetRequestID(r.Context()),
+	}
+}
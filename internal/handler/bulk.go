@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Fedasov/Effective-Mobile/internal/middleware"
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// BulkCreate обрабатывает запрос на пакетное создание подписок
+// @Summary Создать подписки пакетом
+// @Description Создает до 1000 подписок за один запрос. Каждый элемент проверяется отдельно, результаты возвращаются по индексам элементов.
+// @Description В режиме atomic (по умолчанию) при ошибке любого элемента не сохраняется ни один, остальные элементы получают статус 424.
+// @Description В режиме best_effort сохраняются все элементы без ошибок
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body model.BulkCreateRequest true "Подписки для создания"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 200 {object} model.BulkResponse
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных, режим или размер пакета"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/bulk [post]
+func (h *SubscriptionHandler) BulkCreate(w http.ResponseWriter, r *http.Request) {
+	var req model.BulkCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.service.BulkCreate(req, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bulkResponse(r, result, http.StatusCreated))
+}
+
+// BulkUpdate обрабатывает запрос на пакетное обновление подписок
+// @Summary Обновить подписки пакетом
+// @Description Перезаписывает до 1000 подписок за один запрос. Поле version элемента работает как If-Match для этой подписки.
+// @Description Пересечение периодов обрабатывается по политике OVERLAP_POLICY, как при обновлении одной подписки.
+// @Description Режимы atomic и best_effort работают так же, как при пакетном создании
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body model.BulkUpdateRequest true "Подписки для обновления"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 200 {object} model.BulkResponse
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных, режим или размер пакета"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/bulk [put]
+func (h *SubscriptionHandler) BulkUpdate(w http.ResponseWriter, r *http.Request) {
+	var req model.BulkUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.service.BulkUpdate(req, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bulkResponse(r, result, http.StatusOK))
+}
+
+// BulkDelete обрабатывает запрос на пакетное удаление подписок
+// @Summary Удалить подписки пакетом
+// @Description Помечает удаленными до 1000 подписок по списку ID.
+// @Description Режимы atomic и best_effort работают так же, как при пакетном создании
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body model.BulkDeleteRequest true "ID подписок для удаления"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 200 {object} model.BulkResponse
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных, режим или размер пакета"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/bulk/delete [post]
+func (h *SubscriptionHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	var req model.BulkDeleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	result, err := h.service.BulkDelete(req, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, bulkResponse(r, result, http.StatusNoContent))
+}
+
+// bulkResponse строит ответ пакетной операции. successStatus - статус успешно выполненного элемента
+func bulkResponse(r *http.Request, result *model.BulkResult, successStatus int) model.BulkResponse {
+	requestID := middleware.GetRequestID(r.Context())
+	resp := model.BulkResponse{
+		Mode:    result.Mode,
+		Results: make([]model.BulkItemResult, 0, len(result.Items)),
+	}
+
+	for i, item := range result.Items {
+		itemResult := model.BulkItemResult{Index: i, Line: item.Line, ID: item.ID}
+
+		if item.Err != nil {
+			status, errResp := errorResponse(item.Err, requestID)
+			itemResult.Status = status
+			itemResult.Error = &errResp
+			resp.Failed++
+		} else {
+			itemResult.Status = successStatus
+			itemResult.Subscription = item.Subscription
+			resp.Succeeded++
+		}
+
+		resp.Results = append(resp.Results, itemResult)
+	}
+
+	resp.Committed = result.Mode == model.BulkBestEffort || resp.Failed == 0
+	return resp
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/service"
+
+	"github.com/gorilla/mux"
+)
+
+type CatalogHandler struct {
+	service service.CatalogService
+}
+
+func NewCatalogHandler(service service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{service: service}
+}
+
+// Create обрабатывает запрос на добавление сервиса в каталог
+// @Summary Добавить сервис в каталог
+// @Description Создает сервис с названием и синонимами. Названия сопоставляются без учета регистра и лишних пробелов
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param input body model.ServiceRequest true "Данные сервиса"
+// @Success 201 {object} model.Service
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных"
+// @Failure 409 {object} model.ErrorResponse "Название или синоним уже используется другим сервисом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/services [post]
+func (h *CatalogHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req model.ServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	svc, err := h.service.Create(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, svc)
+}
+
+// GetByID обрабатывает запрос на получение сервиса по ID
+// @Summary Получить сервис по ID
+// @Tags services
+// @Produce json
+// @Param id path int true "ID сервиса"
+// @Success 200 {object} model.Service
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Сервис не найден"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/services/{id} [get]
+func (h *CatalogHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	svc, err := h.service.GetByID(uint32(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, svc)
+}
+
+// List обрабатывает запрос на получение каталога сервисов
+// @Summary Получить каталог сервисов
+// @Tags services
+// @Produce json
+// @Param category query string false "Категория сервиса"
+// @Success 200 {array} model.Service
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/services [get]
+func (h *CatalogHandler) List(w http.ResponseWriter, r *http.Request) {
+	services, err := h.service.List(r.URL.Query().Get("category"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, services)
+}
+
+// Update обрабатывает запрос на изменение сервиса
+// @Summary Обновить сервис
+// @Description Перезаписывает сервис и его синонимы. При переименовании новое название записывается во все подписки сервиса
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сервиса"
+// @Param input body model.ServiceRequest true "Новые данные сервиса"
+// @Param X-Actor header string false "Автор изменения для журнала изменений подписок"
+// @Success 200 {object} model.Service
+// @Failure 400 {object} model.ErrorResponse "Неверные данные"
+// @Failure 404 {object} model.ErrorResponse "Сервис не найден"
+// @Failure 409 {object} model.ErrorResponse "Название или синоним уже используется другим сервисом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/services/{id} [put]
+func (h *CatalogHandler) Update(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	var req model.ServiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	svc, err := h.service.Update(uint32(id), req, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, svc)
+}
+
+// Delete обрабатывает запрос на удаление сервиса
+// @Summary Удалить сервис
+// @Description Удаляет сервис из каталога, если на него не оформлено ни одной подписки
+// @Tags services
+// @Param id path int true "ID сервиса"
+// @Success 204 "Сервис успешно удален"
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Сервис не найден"
+// @Failure 409 {object} model.ErrorResponse "На сервис оформлены подписки"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/services/{id} [delete]
+func (h *CatalogHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	if err := h.service.Delete(uint32(id)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Merge обрабатывает запрос на объединение сервисов
+// @Summary Объединить сервисы
+// @Description Переносит подписки и синонимы сервиса source_id в сервис из пути и удаляет source_id.
+// @Description Используется, когда один сервис заведен под разными названиями.
+// @Description Если после переноса подписки пользователя пересекались бы по периоду с его подписками на оставшийся сервис,
+// @Description объединение отклоняется с ошибкой 409, в сообщении перечислены ID пересекающихся подписок
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path int true "ID сервиса, который остается"
+// @Param input body model.ServiceMergeRequest true "Объединяемый сервис"
+// @Param X-Actor header string false "Автор изменения для журнала изменений подписок"
+// @Success 200 {object} model.Service
+// @Failure 400 {object} model.ErrorResponse "Неверные данные"
+// @Failure 404 {object} model.ErrorResponse "Сервис не найден"
+// @Failure 409 {object} model.ErrorResponse "Подписки пересекаются по периоду"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/services/{id}/merge [post]
+func (h *CatalogHandler) Merge(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	var req model.ServiceMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	svc, err := h.service.Merge(uint32(id), req, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, svc)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// setETag выставляет ETag ответа по версии подписки
+func setETag(w http.ResponseWriter, sub *model.Subscription) {
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, sub.Version))
+}
+
+// parseIfMatch возвращает ожидаемую клиентом версию из заголовка If-Match.
+// nil означает, что условие не задано или задано как "*"
+func parseIfMatch(r *http.Request) (*uint32, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return nil, fmt.Errorf("If-Match must contain a single ETag: %w", model.ErrPreconditionFailed)
+	}
+
+	version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("If-Match does not match any version: %w", model.ErrPreconditionFailed)
+	}
+
+	result := uint32(version)
+	return &result, nil
+}
@@ -0,0 +1,293 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/export"
+	"github.com/Fedasov/Effective-Mobile/internal/middleware"
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+)
+
+// exportStream откладывает отправку заголовков ответа до первой строки выгрузки,
+// чтобы ошибки, возникшие до начала передачи данных, можно было вернуть обычным JSON-ответом
+type exportStream struct {
+	w      http.ResponseWriter
+	format export.Format
+	name   string
+	header []interface{}
+	writer export.Writer
+}
+
+func newExportStream(w http.ResponseWriter, format export.Format, name string, header ...interface{}) *exportStream {
+	return &exportStream{w: w, format: format, name: name, header: header}
+}
+
+func (s *exportStream) start() error {
+	writer, err := export.NewWriter(s.format, s.w, s.name)
+	if err != nil {
+		return err
+	}
+
+	s.w.Header().Set("Content-Type", s.format.ContentType())
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, s.name, s.format))
+	s.w.WriteHeader(http.StatusOK)
+
+	s.writer = writer
+	return s.writer.WriteRow(s.header...)
+}
+
+func (s *exportStream) WriteRow(cells ...interface{}) error {
+	if s.writer == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	return s.writer.WriteRow(cells...)
+}
+
+// Close завершает выгрузку; пустая выгрузка содержит только строку заголовков
+func (s *exportStream) Close() error {
+	if s.writer == nil {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	return s.writer.Close()
+}
+
+// fail сообщает об ошибке выгрузки. Если данные уже начали передаваться, статус изменить нельзя:
+// ошибка записывается в лог, а клиент получает оборванный файл
+func (s *exportStream) fail(r *http.Request, err error) {
+	if s.writer == nil {
+		writeError(s.w, r, err)
+		return
+	}
+
+	log.Printf("[%s] Export interrupted: %v", middleware.GetRequestID(r.Context()), err)
+}
+
+// exportFormat выбирает формат выгрузки по параметру format или заголовку Accept.
+// Если формат не поддерживается, отправляет 406 и возвращает ok = false
+func exportFormat(w http.ResponseWriter, r *http.Request) (export.Format, bool) {
+	format, ok := export.Negotiate(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if !ok {
+		writeJSON(w, http.StatusNotAcceptable, model.ErrorResponse{
+			Code:      model.CodeNotAcceptable,
+			Message:   "supported export formats: csv, xlsx",
+			RequestID: middleware.GetRequestID(r.Context()),
+		})
+	}
+
+	return format, ok
+}
+
+// ExportList обрабатывает запрос на выгрузку списка подписок
+// @Summary Выгрузить список подписок
+// @Description Выгружает все подписки, подходящие под фильтры списка, в CSV или XLSX. Пагинация не применяется, строки передаются потоком.
+// @Description Формат выбирается параметром format, а если он не задан - заголовком Accept (text/csv или
+// @Description application/vnd.openxmlformats-officedocument.spreadsheetml.sheet), по умолчанию CSV
+// @Tags export
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param format query string false "Формат выгрузки" Enums(csv, xlsx)
+// @Param user_id query string false "ID пользователя"
+// @Param service_name query string false "Название или синоним сервиса"
+// @Param service_name_prefix query string false "Начало названия сервиса без учета регистра"
+// @Param min_price query string false "Минимальная цена в единицах валюты, десятичное число, например 199.99"
+// @Param max_price query string false "Максимальная цена в единицах валюты, десятичное число, например 199.99"
+// @Param active_at query string false "Подписка активна на дату (YYYY-MM-DD или MM-YYYY)"
+// @Param start_from query string false "Дата начала не раньше (YYYY-MM-DD или MM-YYYY)"
+// @Param start_to query string false "Дата начала не позже (YYYY-MM-DD или MM-YYYY)"
+// @Param end_from query string false "Дата окончания не раньше (YYYY-MM-DD или MM-YYYY)"
+// @Param end_to query string false "Дата окончания не позже (YYYY-MM-DD или MM-YYYY)"
+// @Param in_trial query bool false "Только подписки в пробном периоде на текущую дату"
+// @Param trial_ends_within query int false "Только подписки, пробный период которых закончится в ближайшие N дней"
+// @Param sort query string false "Поле сортировки (по умолчанию id)" Enums(id, service_name, price, user_id, start_date, end_date)
+// @Param order query string false "Направление сортировки (по умолчанию asc)" Enums(asc, desc)
+// @Param include_deleted query bool false "Включить удаленные подписки"
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры фильтрации"
+// @Failure 406 {object} model.ErrorResponse "Формат не поддерживается"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/export [get]
+func (h *SubscriptionHandler) ExportList(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := listRequest(r)
+	if err != nil {
+		writeBadRequest(w, r, err.Error())
+		return
+	}
+
+	stream := newExportStream(w, format, "subscriptions",
+		"id", "service_id", "service_name", "price", "currency", "billing_period", "billing_interval", "anchor_day",
+		"trial_days", "user_id", "start_date", "end_date", "version", "updated_at", "deleted_at")
+
+	err = h.service.Export(req, func(sub *model.Subscription) error {
+		return stream.WriteRow(sub.ID, sub.ServiceID, sub.ServiceName, sub.Price, sub.Currency,
+			sub.BillingPeriod, sub.BillingInterval, sub.AnchorDay, sub.TrialDays, sub.UserID.String(),
+			formatDate(&sub.StartDate), formatDate(sub.EndDate), sub.Version,
+			sub.UpdatedAt.UTC().Format(time.RFC3339), formatTimestamp(sub.DeletedAt))
+	})
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil {
+		stream.fail(r, err)
+	}
+}
+
+// ExportTotalCost обрабатывает запрос на выгрузку общей стоимости подписок
+// @Summary Выгрузить общую стоимость подписок
+// @Description Рассчитывает общую стоимость так же, как /subscriptions/total-cost, и возвращает ее таблицей CSV или XLSX
+// @Tags export
+// @Accept json
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param format query string false "Формат выгрузки" Enums(csv, xlsx)
+// @Param input body model.TotalCostRequest true "Параметры расчета"
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры"
+// @Failure 406 {object} model.ErrorResponse "Формат не поддерживается"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/total-cost/export [post]
+func (h *SubscriptionHandler) ExportTotalCost(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.TotalCostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	total, err := h.service.CalculateTotalCost(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	userID := ""
+	if req.UserID != nil {
+		userID = req.UserID.String()
+	}
+	serviceName := ""
+	if req.ServiceName != nil {
+		serviceName = *req.ServiceName
+	}
+
+	stream := newExportStream(w, format, "total-cost",
+		"start_date", "end_date", "user_id", "service_name", "months", "total_cost", "currency")
+
+	err = stream.WriteRow(req.StartDate, req.EndDate, userID, serviceName, total.Months, total.TotalCost, total.Currency)
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil {
+		stream.fail(r, err)
+	}
+}
+
+// ExportCostBreakdown обрабатывает запрос на выгрузку помесячной стоимости подписок
+// @Summary Выгрузить помесячную стоимость подписок
+// @Description Рассчитывает помесячную стоимость так же, как /subscriptions/cost-breakdown, и возвращает ее таблицей CSV или XLSX.
+// @Description При группировке каждая группа месяца выгружается отдельной строкой; последняя строка содержит итог за период.
+// @Description Колонка currency содержит валюту отчета, в которую пересчитаны суммы
+// @Tags export
+// @Accept json
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param format query string false "Формат выгрузки" Enums(csv, xlsx)
+// @Param input body model.CostBreakdownRequest true "Параметры расчета"
+// @Success 200 {file} file "Файл выгрузки"
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры"
+// @Failure 406 {object} model.ErrorResponse "Формат не поддерживается"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/cost-breakdown/export [post]
+func (h *SubscriptionHandler) ExportCostBreakdown(w http.ResponseWriter, r *http.Request) {
+	format, ok := exportFormat(w, r)
+	if !ok {
+		return
+	}
+
+	var req model.CostBreakdownRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	breakdown, err := h.service.CostBreakdown(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	header := []interface{}{"month", "total_cost", "currency"}
+	if req.GroupBy != nil {
+		header = []interface{}{"month", *req.GroupBy, "total_cost", "currency"}
+	}
+
+	stream := newExportStream(w, format, "cost-breakdown", header...)
+
+	err = writeCostBreakdown(stream, breakdown, req.GroupBy != nil)
+	if err == nil {
+		err = stream.Close()
+	}
+	if err != nil {
+		stream.fail(r, err)
+	}
+}
+
+// writeCostBreakdown записывает строки помесячной стоимости и итоговую строку за период.
+// Каждая строка содержит валюту отчета, в которую пересчитаны суммы
+func writeCostBreakdown(stream *exportStream, breakdown *model.CostBreakdownResponse, grouped bool) error {
+	for _, month := range breakdown.Months {
+		if !grouped {
+			if err := stream.WriteRow(month.Month, month.TotalCost, breakdown.Currency); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, group := range month.Groups {
+			if err := stream.WriteRow(month.Month, group.Key, group.TotalCost, breakdown.Currency); err != nil {
+				return err
+			}
+		}
+	}
+
+	if grouped {
+		return stream.WriteRow("total", "", breakdown.TotalCost, breakdown.Currency)
+	}
+
+	return stream.WriteRow("total", breakdown.TotalCost, breakdown.Currency)
+}
+
+// formatDate форматирует дату подписки в формате API "YYYY-MM-DD"
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+
+	return date.Format(validator.DateLayout)
+}
+
+func formatTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/Fedasov/Effective-Mobile/internal/middleware"
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// Ограничения загружаемого файла импорта
+const (
+	maxImportFileSize = 32 << 20
+	importMemoryLimit = 8 << 20
+	importFileField   = "file"
+)
+
+// Import обрабатывает загрузку CSV-файла с подписками
+// @Summary Импортировать подписки из CSV
+// @Description Принимает CSV-файл с заголовком и колонками service_name, price, currency, billing_period, billing_interval, anchor_day, trial_days, user_id, start_date, end_date (даты в формате YYYY-MM-DD или MM-YYYY; обязательны service_name, price, user_id и start_date).
+// @Description При dry_run=true строки только проверяются без сохранения, и возвращаются ошибки каждой строки.
+// @Description Иначе корректные строки сохраняются в режиме mode так же, как при пакетном создании
+// @Tags subscriptions
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "CSV-файл с подписками"
+// @Param dry_run query bool false "Только проверить файл без сохранения"
+// @Param mode query string false "Режим сохранения (по умолчанию atomic)" Enums(atomic, best_effort)
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 200 {object} model.ImportResponse
+// @Failure 400 {object} model.ErrorResponse "Неверный файл или параметры"
+// @Failure 413 {object} model.ErrorResponse "Файл слишком большой"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/import [post]
+func (h *SubscriptionHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseBoolQuery(r, "dry_run")
+	if err != nil {
+		writeBadRequest(w, r, err.Error())
+		return
+	}
+
+	file, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	mode := model.BulkMode(r.URL.Query().Get("mode"))
+	result, err := h.service.ImportCSV(file, mode, dryRun, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := model.ImportResponse{
+		DryRun:       result.DryRun,
+		BulkResponse: bulkResponse(r, &result.BulkResult, http.StatusCreated),
+	}
+	if result.DryRun {
+		resp.Committed = false
+		for i := range resp.Results {
+			if resp.Results[i].Error == nil {
+				resp.Results[i].Status = http.StatusOK
+			}
+		}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// uploadedFile разбирает multipart-форму и открывает файл из поля importFileField.
+// Если файл получить не удалось, ответ с ошибкой уже записан и возвращается false
+func uploadedFile(w http.ResponseWriter, r *http.Request) (multipart.File, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	if err := r.ParseMultipartForm(importMemoryLimit); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeJSON(w, http.StatusRequestEntityTooLarge, model.ErrorResponse{
+				Code:      model.CodeTooLarge,
+				Message:   "file is too large",
+				RequestID: middleware.GetRequestID(r.Context()),
+			})
+			return nil, false
+		}
+		writeBadRequest(w, r, "invalid multipart form: "+err.Error())
+		return nil, false
+	}
+
+	file, _, err := r.FormFile(importFileField)
+	if err != nil {
+		r.MultipartForm.RemoveAll()
+		writeBadRequest(w, r, "missing file field "+importFileField)
+		return nil, false
+	}
+
+	return file, true
+}
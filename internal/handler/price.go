@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+
+	"github.com/gorilla/mux"
+)
+
+// PriceSchedule обрабатывает запрос на получение цен подписки по периодам
+// @Summary Получить график цен подписки
+// @Description Возвращает базовую цену с месяца начала подписки и последующие изменения цены по возрастанию месяца.
+// @Description Каждая цена действует с месяца effective_from до следующего изменения
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {array} model.PriceChange
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/prices [get]
+func (h *SubscriptionHandler) PriceSchedule(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	schedule, err := h.service.PriceSchedule(uint32(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, schedule)
+}
+
+// SchedulePriceChange обрабатывает запрос на изменение цены подписки с указанного месяца
+// @Summary Запланировать изменение цены подписки
+// @Description Устанавливает цену, действующую с месяца effective_from (не раньше следующего месяца) до следующего изменения.
+// @Description Повторное изменение с тем же месяцем заменяет цену. Стоимость прошедших и текущего месяцев не меняется
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param input body model.PriceChangeRequest true "Месяц и новая цена"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 201 {array} model.PriceChange
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/prices [post]
+func (h *SubscriptionHandler) SchedulePriceChange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req model.PriceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	schedule, err := h.service.SchedulePriceChange(uint32(id), req, expectedVersion, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, schedule)
+}
+
+// CancelPriceChange обрабатывает запрос на отмену запланированного изменения цены
+// @Summary Отменить изменение цены подписки
+// @Description Удаляет изменение цены с указанного месяца. Отменить можно только изменение, которое начинается в будущем месяце
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param month path string true "Месяц изменения (MM-YYYY)"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 204 "Изменение цены отменено"
+// @Failure 400 {object} model.ErrorResponse "Неверный ID или месяц"
+// @Failure 404 {object} model.ErrorResponse "Изменение цены не найдено"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/prices/{month} [delete]
+func (h *SubscriptionHandler) CancelPriceChange(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.service.CancelPriceChange(uint32(id), vars["month"], expectedVersion, auditMeta(r)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
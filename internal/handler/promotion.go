@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+
+	"github.com/gorilla/mux"
+)
+
+// Promotions обрабатывает запрос на получение промо-цен подписки
+// @Summary Получить промо-цены подписки
+// @Description Возвращает акции подписки по возрастанию даты начала. Во время акции списания идут по цене акции вместо обычной
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {array} model.Promotion
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/promotions [get]
+func (h *SubscriptionHandler) Promotions(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	promotions, err := h.service.Promotions(uint32(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, promotions)
+}
+
+// AddPromotion обрабатывает запрос на добавление промо-цены подписки
+// @Summary Добавить промо-цену подписки
+// @Description Устанавливает цену акции (0 - бесплатно) с start_date по end_date включительно. Акция начинается в периоде подписки,
+// @Description не раньше следующего месяца, чтобы не менять стоимость уже оплаченных периодов,
+// @Description и не пересекается с другими акциями. В пробном периоде подписка бесплатна независимо от акций
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param input body model.PromotionRequest true "Период и цена акции"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 201 {object} model.Promotion
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 409 {object} model.ErrorResponse "Период пересекается с другой акцией"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/promotions [post]
+func (h *SubscriptionHandler) AddPromotion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req model.PromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	promotion, err := h.service.AddPromotion(uint32(id), req, expectedVersion, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, promotion)
+}
+
+// DeletePromotion обрабатывает запрос на удаление промо-цены подписки
+// @Summary Удалить промо-цену подписки
+// @Description Удаляет акцию; списания в ее период снова идут по обычной цене. Удалить можно только акцию, которая начинается в будущем месяце
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param promotion_id path int true "ID акции"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 204 "Акция удалена"
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Акция не найдена"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/promotions/{promotion_id} [delete]
+func (h *SubscriptionHandler) DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	promotionID, err := strconv.Atoi(vars["promotion_id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid promotion ID")
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeletePromotion(uint32(id), uint32(promotionID), expectedVersion, auditMeta(r)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
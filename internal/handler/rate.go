@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/service"
+)
+
+type RateHandler struct {
+	service service.RateService
+}
+
+func NewRateHandler(service service.RateService) *RateHandler {
+	return &RateHandler{service: service}
+}
+
+// Upsert обрабатывает запрос на загрузку курсов валют
+// @Summary Загрузить курсы валют
+// @Description Сохраняет курсы валют по месяцам: rate - стоимость единицы валюты в рублях. Курс действует с месяца month
+// @Description до следующего загруженного курса этой валюты. Курсы тех же месяцев заменяются; при ошибке в любом элементе ничего не сохраняется.
+// @Description RUB - базовая валюта с курсом 1: курсы RUB не принимаются и отклоняются с ошибкой поля currency
+// @Tags exchange-rates
+// @Accept json
+// @Produce json
+// @Param input body []model.ExchangeRateRequest true "Курсы валют"
+// @Success 200 {array} model.ExchangeRate
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/exchange-rates [put]
+func (h *RateHandler) Upsert(w http.ResponseWriter, r *http.Request) {
+	var reqs []model.ExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	rates, err := h.service.Upsert(reqs)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rates)
+}
+
+// Import обрабатывает загрузку CSV-файла с курсами валют
+// @Summary Импортировать курсы валют из CSV
+// @Description Принимает CSV-файл с заголовком currency,month,rate (месяц в формате MM-YYYY) и сохраняет курсы так же, как PUT /exchange-rates.
+// @Description Строки с базовой валютой RUB отклоняются
+// @Tags exchange-rates
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "CSV-файл с курсами"
+// @Success 200 {object} model.ExchangeRateImportResponse
+// @Failure 400 {object} model.ErrorResponse "Неверный файл"
+// @Failure 413 {object} model.ErrorResponse "Файл слишком большой"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/exchange-rates/import [post]
+func (h *RateHandler) Import(w http.ResponseWriter, r *http.Request) {
+	file, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	loaded, err := h.service.ImportCSV(file)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.ExchangeRateImportResponse{Loaded: loaded})
+}
+
+// List обрабатывает запрос на получение курсов валют
+// @Summary Получить курсы валют
+// @Description Возвращает загруженные курсы - стоимость единицы валюты в базовой валюте RUB. Курс RUB всегда равен 1 и не хранится
+// @Tags exchange-rates
+// @Produce json
+// @Param currency query string false "Код валюты ISO 4217"
+// @Success 200 {array} model.ExchangeRate
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/exchange-rates [get]
+func (h *RateHandler) List(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.List(r.URL.Query().Get("currency"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rates)
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/Fedasov/Effective-Mobile/internal/middleware"
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// retryAfterSeconds подсказывает клиенту, через сколько секунд повторить запрос при недоступности хранилища
+const retryAfterSeconds = "5"
+
+// parseBoolQuery разбирает необязательный логический параметр запроса
+func parseBoolQuery(r *http.Request, name string) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s", name)
+	}
+
+	return result, nil
+}
+
+// writeJSON отправляет ответ в формате JSON с указанным статусом
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeBadRequest отправляет ошибку разбора запроса (некорректный JSON, параметр пути и т.п.)
+func writeBadRequest(w http.ResponseWriter, r *http.Request, message string) {
+	writeJSON(w, http.StatusBadRequest, model.ErrorResponse{
+		Code:      model.CodeBadRequest,
+		Message:   message,
+		RequestID: middleware.GetRequestID(r.Context()),
+	})
+}
+
+// writeUnsupportedMediaType отправляет ошибку 415 для тела запроса с неподдерживаемым Content-Type
+func writeUnsupportedMediaType(w http.ResponseWriter, r *http.Request, message string) {
+	writeJSON(w, http.StatusUnsupportedMediaType, model.ErrorResponse{
+		Code:      model.CodeUnsupportedMediaType,
+		Message:   message,
+		RequestID: middleware.GetRequestID(r.Context()),
+	})
+}
+
+// writeError отправляет ошибку сервиса, определяя статус и код по ее типу
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status, resp := errorResponse(err, middleware.GetRequestID(r.Context()))
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", retryAfterSeconds)
+	}
+
+	writeJSON(w, status, resp)
+}
+
+// errorResponse определяет HTTP-статус и тело ответа по типу ошибки сервиса.
+// Текст внутренних ошибок не раскрывается клиенту и попадает только в лог
+func errorResponse(err error, requestID string) (int, model.ErrorResponse) {
+	resp := model.ErrorResponse{
+		Message:   err.Error(),
+		RequestID: requestID,
+	}
+
+	var status int
+	var validationErr *model.ValidationError
+
+	switch {
+	case errors.As(err, &validationErr):
+		status = http.StatusBadRequest
+		resp.Code = model.CodeValidation
+		resp.Details = validationErr.Fields
+	case errors.Is(err, model.ErrValidation):
+		status = http.StatusBadRequest
+		resp.Code = model.CodeValidation
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+		resp.Code = model.CodeNotFound
+	case errors.Is(err, model.ErrConflict):
+		status = http.StatusConflict
+		resp.Code = model.CodeConflict
+	case errors.Is(err, model.ErrConstraint):
+		status = http.StatusUnprocessableEntity
+		resp.Code = model.CodeConstraint
+	case errors.Is(err, model.ErrPreconditionFailed):
+		status = http.StatusPreconditionFailed
+		resp.Code = model.CodePrecondition
+	case errors.Is(err, model.ErrOverflow):
+		status = http.StatusUnprocessableEntity
+		resp.Code = model.CodeOverflow
+	case errors.Is(err, model.ErrBatchAborted):
+		status = http.StatusFailedDependency
+		resp.Code = model.CodeBatchAborted
+	case errors.Is(err, model.ErrUnavailable):
+		log.Printf("[%s] Storage unavailable: %v", requestID, err)
+		status = http.StatusServiceUnavailable
+		resp.Code = model.CodeUnavailable
+		resp.Message = "service temporarily unavailable"
+	default:
+		log.Printf("[%s] Internal error: %v", requestID, err)
+		status = http.StatusInternalServerError
+		resp.Code = model.CodeInternal
+		resp.Message = "internal server error"
+	}
+
+	return status, resp
+}
@@ -2,15 +2,24 @@ package handler
 
 import (
 	"encoding/json"
+	"mime"
 	"net/http"
 	"strconv"
 
+	"github.com/Fedasov/Effective-Mobile/internal/middleware"
 	"github.com/Fedasov/Effective-Mobile/internal/model"
 	"github.com/Fedasov/Effective-Mobile/internal/service"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 )
 
+// Заголовки идемпотентного создания подписки
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 type SubscriptionHandler struct {
 	service service.SubscriptionService
 }
@@ -21,30 +30,54 @@ func NewSubscriptionHandler(service service.SubscriptionService) *SubscriptionHa
 
 // Create обрабатывает запрос на создание подписки
 // @Summary Создать новую подписку
-// @Description Создает новую запись о подписке пользователя
+// @Description Создает новую запись о подписке пользователя.
+// @Description Пересечение периода с подпиской того же пользователя на тот же сервис обрабатывается по политике OVERLAP_POLICY:
+// @Description reject — ошибка 409, merge — расширение периода существующей подписки, allow — создание с предупреждением в поле warnings
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param input body model.SubscriptionCreateRequest true "Данные подписки"
+// @Param Idempotency-Key header string false "Ключ идемпотентности: повтор запроса с тем же ключом возвращает исходную подписку"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
 // @Success 201 {object} model.Subscription
-// @Failure 400 {object} map[string]string "Неверный формат данных"
+// @Header 201 {string} Idempotent-Replayed "true, если ответ возвращен по ранее использованному ключу"
+// @Failure 400 {object} model.ErrorResponse "Неверный формат данных"
+// @Failure 409 {object} model.ErrorResponse "Конфликт с существующими данными или ключ идемпотентности использован с другим запросом"
+// @Failure 422 {object} model.ErrorResponse "Нарушено ограничение данных"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
 // @Router /api/v1/subscriptions [post]
 func (h *SubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req model.SubscriptionCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
 		return
 	}
 
-	subscription, err := h.service.Create(req)
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" {
+		subscription, err := h.service.Create(req, auditMeta(r))
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		setETag(w, subscription)
+		writeJSON(w, http.StatusCreated, subscription)
+		return
+	}
+
+	subscription, replayed, err := h.service.CreateIdempotent(req, key, auditMeta(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(subscription)
+	if replayed {
+		w.Header().Set(idempotentReplayedHeader, "true")
+	}
+	setETag(w, subscription)
+	writeJSON(w, http.StatusCreated, subscription)
 }
 
 // GetByID обрабатывает запрос на получение подписки по ID
@@ -53,102 +86,281 @@ func (h *SubscriptionHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags subscriptions
 // @Produce json
 // @Param id path int true "ID подписки"
+// @Param include_deleted query bool false "Вернуть подписку, даже если она удалена"
 // @Success 200 {object} model.Subscription
-// @Failure 400 {object} map[string]string "Неверный ID"
-// @Failure 404 {object} map[string]string "Подписка не найдена"
+// @Header 200 {string} ETag "Версия подписки"
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
 // @Router /api/v1/subscriptions/{id} [get]
 func (h *SubscriptionHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	includeDeleted, err := parseBoolQuery(r, "include_deleted")
+	if err != nil {
+		writeBadRequest(w, r, err.Error())
 		return
 	}
 
-	subscription, err := h.service.GetByID(uint32(id))
+	subscription, err := h.service.GetByID(uint32(id), includeDeleted)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(subscription)
+	setETag(w, subscription)
+	writeJSON(w, http.StatusOK, subscription)
 }
 
 // Update обрабатывает запрос на обновление подписки
 // @Summary Обновить подписку
-// @Description Обновляет информацию о существующей подписке
+// @Description Обновляет информацию о существующей подписке.
+// @Description Пересечение периода с другой подпиской того же пользователя на тот же сервис обрабатывается по политике OVERLAP_POLICY:
+// @Description reject — ошибка 409, merge — поглощение пересекающейся подписки с расширением периода, allow — изменение с предупреждением в поле warnings
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param id path int true "ID подписки"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
 // @Param input body model.SubscriptionCreateRequest true "Новые данные подписки"
 // @Success 200 {object} model.Subscription
-// @Failure 400 {object} map[string]string "Неверные данные"
-// @Failure 404 {object} map[string]string "Подписка не найдена"
+// @Failure 400 {object} model.ErrorResponse "Неверные данные"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 409 {object} model.ErrorResponse "Конфликт с существующими данными"
+// @Failure 422 {object} model.ErrorResponse "Нарушено ограничение данных"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
 // @Router /api/v1/subscriptions/{id} [put]
 func (h *SubscriptionHandler) Update(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	var req model.SubscriptionCreateRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	subscription, err := h.service.Update(uint32(id), req, expectedVersion, auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, subscription)
+	writeJSON(w, http.StatusOK, subscription)
+}
+
+// Patch обрабатывает запрос на частичное обновление подписки
+// @Summary Частично обновить подписку
+// @Description Обновляет только переданные поля подписки по семантике JSON Merge Patch. Передача end_date: null отменяет дату окончания.
+// @Description Пересечение периода с другими подписками обрабатывается по политике OVERLAP_POLICY, как при полном обновлении
+// @Tags subscriptions
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Param input body model.SubscriptionPatchRequest true "Изменяемые поля подписки"
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} model.ErrorResponse "Неверные данные"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 409 {object} model.ErrorResponse "Конфликт с существующими данными"
+// @Failure 415 {object} model.ErrorResponse "Неподдерживаемый тип содержимого"
+// @Failure 422 {object} model.ErrorResponse "Нарушено ограничение данных"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id} [patch]
+func (h *SubscriptionHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "" &&
+		mediaType != "application/json" && mediaType != "application/merge-patch+json" {
+		writeUnsupportedMediaType(w, r, "content type must be application/merge-patch+json or application/json")
+		return
+	}
+
+	var req model.SubscriptionPatchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
 		return
 	}
 
-	subscription, err := h.service.Update(uint32(id), req)
+	subscription, err := h.service.Patch(uint32(id), req, expectedVersion, auditMeta(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(subscription)
+	setETag(w, subscription)
+	writeJSON(w, http.StatusOK, subscription)
 }
 
 // Delete обрабатывает запрос на удаление подписки
 // @Summary Удалить подписку
-// @Description Удаляет запись о подписке по её идентификатору
+// @Description Помечает подписку удаленной. Удаленная подписка не попадает в выборки и отчеты, ее можно восстановить до окончательной очистки
 // @Tags subscriptions
 // @Param id path int true "ID подписки"
+// @Param If-Match header string false "ETag подписки, полученный ранее; при несовпадении версии возвращается 412"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
 // @Success 204 "Подписка успешно удалена"
-// @Failure 400 {object} map[string]string "Неверный ID"
-// @Failure 404 {object} map[string]string "Подписка не найдена"
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 412 {object} model.ErrorResponse "Подписка была изменена другим клиентом"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
 // @Router /api/v1/subscriptions/{id} [delete]
 func (h *SubscriptionHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid ID", http.StatusBadRequest)
+		writeBadRequest(w, r, "invalid ID")
 		return
 	}
 
-	if err := h.service.Delete(uint32(id)); err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+	expectedVersion, err := parseIfMatch(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.service.Delete(uint32(id), expectedVersion, auditMeta(r)); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// Restore обрабатывает запрос на восстановление удаленной подписки
+// @Summary Восстановить подписку
+// @Description Снимает отметку об удалении с подписки, если она еще не была окончательно очищена.
+// @Description Пересечение периода с подписками того же пользователя на тот же сервис обрабатывается по политике OVERLAP_POLICY, как при обновлении
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Param X-Actor header string false "Автор изменения для журнала изменений"
+// @Success 200 {object} model.Subscription
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 409 {object} model.ErrorResponse "Подписка не удалена или пересекается с другой подпиской"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/restore [post]
+func (h *SubscriptionHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	subscription, err := h.service.Restore(uint32(id), auditMeta(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	setETag(w, subscription)
+	writeJSON(w, http.StatusOK, subscription)
+}
+
+// History обрабатывает запрос на получение журнала изменений подписки
+// @Summary Получить историю изменений подписки
+// @Description Возвращает изменения подписки в порядке их выполнения со снимками до и после изменения,
+// @Description автором из заголовка X-Actor и ID запроса. История доступна и для удаленных подписок
+// @Tags subscriptions
+// @Produce json
+// @Param id path int true "ID подписки"
+// @Success 200 {array} model.AuditEntry
+// @Failure 400 {object} model.ErrorResponse "Неверный ID"
+// @Failure 404 {object} model.ErrorResponse "Подписка не найдена"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/{id}/history [get]
+func (h *SubscriptionHandler) History(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid ID")
+		return
+	}
+
+	entries, err := h.service.History(uint32(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
 // List обрабатывает запрос на получение списка подписок
 // @Summary Получить список подписок
-// @Description Возвращает список подписок с поддержкой пагинации
+// @Description Возвращает список подписок с поддержкой фильтрации, сортировки и пагинации.
+// @Description По умолчанию используется пагинация смещением и ответ — массив подписок.
+// @Description При передаче cursor (в том числе пустого) используется курсорная пагинация, а при cursor или with_total ответ оборачивается в конверт {items, next_cursor, total}.
 // @Tags subscriptions
 // @Produce json
 // @Param limit query int false "Лимит записей (по умолчанию 10)"
 // @Param offset query int false "Смещение (по умолчанию 0)"
-// @Success 200 {array} model.Subscription
-// @Failure 500 {object} map[string]string "Внутренняя ошибка сервера"
+// @Param user_id query string false "ID пользователя"
+// @Param service_name query string false "Название или синоним сервиса"
+// @Param service_name_prefix query string false "Начало названия сервиса без учета регистра"
+// @Param min_price query string false "Минимальная цена в единицах валюты, десятичное число, например 199.99"
+// @Param max_price query string false "Максимальная цена в единицах валюты, десятичное число, например 199.99"
+// @Param active_at query string false "Подписка активна на дату (YYYY-MM-DD или MM-YYYY)"
+// @Param start_from query string false "Дата начала не раньше (YYYY-MM-DD или MM-YYYY)"
+// @Param start_to query string false "Дата начала не позже (YYYY-MM-DD или MM-YYYY)"
+// @Param end_from query string false "Дата окончания не раньше (YYYY-MM-DD или MM-YYYY)"
+// @Param end_to query string false "Дата окончания не позже (YYYY-MM-DD или MM-YYYY)"
+// @Param in_trial query bool false "Только подписки в пробном периоде на текущую дату"
+// @Param trial_ends_within query int false "Только подписки, пробный период которых закончится в ближайшие N дней"
+// @Param sort query string false "Поле сортировки (по умолчанию id)" Enums(id, service_name, price, user_id, start_date, end_date)
+// @Param order query string false "Направление сортировки (по умолчанию asc)" Enums(asc, desc)
+// @Param cursor query string false "Курсор следующей страницы; пустое значение включает курсорную пагинацию с первой страницы"
+// @Param with_total query bool false "Вернуть общее количество записей"
+// @Param include_deleted query bool false "Включить удаленные подписки"
+// @Success 200 {array} model.Subscription "Список подписок; при cursor или with_total — объект model.SubscriptionPage"
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры фильтрации"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
 // @Router /api/v1/subscriptions [get]
 func (h *SubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
+	query := r.URL.Query()
+	limitStr := query.Get("limit")
+	offsetStr := query.Get("offset")
 
 	limit := 10
 	offset := 0
@@ -165,39 +377,198 @@ func (h *SubscriptionHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	subscriptions, err := h.service.List(int32(limit), int32(offset))
+	req, err := listRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeBadRequest(w, r, err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(subscriptions)
+	req.Limit = int32(limit)
+	req.Offset = int32(offset)
+	req.Cursor = query.Get("cursor")
+	req.UseCursor = query.Has("cursor")
+
+	if req.WithTotal, err = parseBoolQuery(r, "with_total"); err != nil {
+		writeBadRequest(w, r, err.Error())
+		return
+	}
+
+	page, err := h.service.List(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	// Без курсора и общего количества сохраняем прежний формат ответа — массив подписок
+	if !req.UseCursor && !req.WithTotal {
+		writeJSON(w, http.StatusOK, page.Items)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// listRequest читает из параметров запроса фильтры и сортировку списка подписок
+func listRequest(r *http.Request) (model.SubscriptionListRequest, error) {
+	query := r.URL.Query()
+	req := model.SubscriptionListRequest{
+		UserID:            query.Get("user_id"),
+		ServiceName:       query.Get("service_name"),
+		ServiceNamePrefix: query.Get("service_name_prefix"),
+		MinPrice:          query.Get("min_price"),
+		MaxPrice:          query.Get("max_price"),
+		ActiveAt:          query.Get("active_at"),
+		StartFrom:         query.Get("start_from"),
+		StartTo:           query.Get("start_to"),
+		EndFrom:           query.Get("end_from"),
+		EndTo:             query.Get("end_to"),
+		TrialEndsWithin:   query.Get("trial_ends_within"),
+		Sort:              query.Get("sort"),
+		Order:             query.Get("order"),
+	}
+
+	var err error
+	if req.IncludeDeleted, err = parseBoolQuery(r, "include_deleted"); err != nil {
+		return req, err
+	}
+	if req.InTrial, err = parseBoolQuery(r, "in_trial"); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+// ListByUser обрабатывает запрос на получение подписок пользователя
+// @Summary Получить подписки пользователя
+// @Description Возвращает все подписки пользователя со статусом (active, expired, upcoming) и текущими ежемесячными расходами
+// @Tags users
+// @Produce json
+// @Param user_id path string true "ID пользователя"
+// @Success 200 {object} model.UserSubscriptionsResponse
+// @Failure 400 {object} model.ErrorResponse "Неверный ID пользователя"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/users/{user_id}/subscriptions [get]
+func (h *SubscriptionHandler) ListByUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := uuid.Parse(vars["user_id"])
+	if err != nil {
+		writeBadRequest(w, r, "invalid user ID")
+		return
+	}
+
+	subscriptions, err := h.service.ListByUser(userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, subscriptions)
 }
 
 // GetTotalCost обрабатывает запрос на расчет общей стоимости
 // @Summary Рассчитать общую стоимость
-// @Description Вычисляет общую стоимость подписок за указанный период с возможностью фильтрации
+// @Description Вычисляет общую стоимость подписок за указанный период с учетом количества оплачиваемых месяцев каждой подписки.
+// @Description Цены в других валютах пересчитываются в currency по курсу каждого месяца; by_currency содержит суммы без пересчета
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param input body model.TotalCostRequest true "Параметры расчета"
-// @Success 200 {object} map[string]int "Общая стоимость"
-// @Failure 400 {object} map[string]string "Неверные параметры"
+// @Success 200 {object} model.TotalCostResponse "Общая стоимость"
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры"
+// @Failure 422 {object} model.ErrorResponse "Нет курса валюты за месяц периода или сумма превышает допустимую"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
 // @Router /api/v1/subscriptions/total-cost [post]
 func (h *SubscriptionHandler) GetTotalCost(w http.ResponseWriter, r *http.Request) {
 	var req model.TotalCostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
 		return
 	}
 
 	total, err := h.service.CalculateTotalCost(req)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, total)
+}
+
+// GetCostBreakdown обрабатывает запрос на помесячную разбивку стоимости
+// @Summary Помесячная разбивка стоимости
+// @Description Возвращает стоимость подписок по каждому месяцу периода с возможностью группировки по сервису или пользователю.
+// @Description Период не длиннее 120 месяцев
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body model.CostBreakdownRequest true "Параметры расчета"
+// @Success 200 {object} model.CostBreakdownResponse
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры"
+// @Failure 422 {object} model.ErrorResponse "Нет курса валюты за месяц периода или сумма превышает допустимую"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/cost-breakdown [post]
+func (h *SubscriptionHandler) GetCostBreakdown(w http.ResponseWriter, r *http.Request) {
+	var req model.CostBreakdownRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	breakdown, err := h.service.CostBreakdown(req)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]int32{"total_cost": total})
+	writeJSON(w, http.StatusOK, breakdown)
+}
+
+// GetSpendReport обрабатывает запрос на отчет о расходах с группировкой
+// @Summary Отчет о расходах по сервисам и пользователям
+// @Description Возвращает общую стоимость подписок за период, сгруппированную по сервису, пользователю или обоим полям, с сортировкой и ограничением top-N
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param input body model.SpendReportRequest true "Параметры отчета"
+// @Success 200 {object} model.SpendReportResponse
+// @Failure 400 {object} model.ErrorResponse "Неверные параметры"
+// @Failure 422 {object} model.ErrorResponse "Нет курса валюты за месяц периода или сумма превышает допустимую"
+// @Failure 500 {object} model.ErrorResponse "Внутренняя ошибка сервера"
+// @Failure 503 {object} model.ErrorResponse "Хранилище временно недоступно"
+// @Router /api/v1/subscriptions/spend-report [post]
+func (h *SubscriptionHandler) GetSpendReport(w http.ResponseWriter, r *http.Request) {
+	var req model.SpendReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: "+err.Error())
+		return
+	}
+
+	report, err := h.service.SpendReport(req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// NotFound возвращает ошибку в формате JSON для неизвестных маршрутов
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusNotFound, model.ErrorResponse{
+		Code:      model.CodeNotFound,
+		Message:   "route not found",
+		RequestID: middleware.GetRequestID(r.Context()),
+	})
+}
+
+// MethodNotAllowed возвращает ошибку в формате JSON для неподдерживаемых методов
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusMethodNotAllowed, model.ErrorResponse{
+		Code:      model.CodeBadRequest,
+		Message:   "method not allowed",
+		RequestID: middleware.GetRequestID(r.Context()),
+	})
 }
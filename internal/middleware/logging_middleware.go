@@ -8,7 +8,7 @@ import (
 // loggingMiddleware добавляет логирование запросов
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		log.Printf("%s %s %s", r.RemoteAddr, r.Method, r.URL)
+		log.Printf("[%s] %s %s %s", GetRequestID(r.Context()), r.RemoteAddr, r.Method, r.URL)
 		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditAction - вид изменения подписки в журнале
+type AuditAction string
+
+const (
+	AuditCreate    AuditAction = "create"
+	AuditUpdate    AuditAction = "update"
+	AuditPatch     AuditAction = "patch"
+	AuditDelete    AuditAction = "delete"
+	AuditRestore   AuditAction = "restore"
+	AuditMerge     AuditAction = "merge"
+	AuditCatalog   AuditAction = "catalog"
+	AuditPrice     AuditAction = "price"
+	AuditPromotion AuditAction = "promotion"
+)
+
+// AuditMeta описывает источник изменения: кто и в рамках какого запроса его выполнил
+type AuditMeta struct {
+	Actor     string
+	RequestID string
+}
+
+// AuditEntry - запись журнала изменений подписки
+type AuditEntry struct {
+	ID             int64           `json:"id"`
+	SubscriptionID uint32          `json:"subscription_id"`
+	Action         AuditAction     `json:"action"`
+	Before         json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After          json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Actor          string          `json:"actor,omitempty"`
+	RequestID      string          `json:"request_id,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
@@ -0,0 +1,20 @@
+package model
+
+// Периодичность списаний по подписке
+const (
+	BillingWeekly    = "weekly"
+	BillingMonthly   = "monthly"
+	BillingQuarterly = "quarterly"
+	BillingYearly    = "yearly"
+	// BillingCustom - списание каждые BillingInterval месяцев
+	BillingCustom = "custom"
+)
+
+// BillingIntervals - количество месяцев (для weekly - недель) в периоде списания
+// для периодичностей с фиксированной длиной периода
+var BillingIntervals = map[string]int32{
+	BillingWeekly:    1,
+	BillingMonthly:   1,
+	BillingQuarterly: 3,
+	BillingYearly:    12,
+}
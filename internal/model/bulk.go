@@ -0,0 +1,92 @@
+package model
+
+// BulkMode - режим выполнения пакетной операции
+type BulkMode string
+
+const (
+	// BulkAtomic выполняет все элементы в одной транзакции: ошибка любого элемента отменяет весь пакет
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort сохраняет успешные элементы независимо от ошибок остальных
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+func (m BulkMode) Valid() bool {
+	switch m {
+	case BulkAtomic, BulkBestEffort:
+		return true
+	}
+	return false
+}
+
+type BulkCreateRequest struct {
+	// Mode - atomic (по умолчанию) или best_effort
+	Mode  BulkMode                    `json:"mode,omitempty" example:"atomic"`
+	Items []SubscriptionCreateRequest `json:"items"`
+}
+
+type BulkUpdateItem struct {
+	ID uint32 `json:"id" example:"1"`
+	// Version - ожидаемая версия подписки, аналог заголовка If-Match
+	Version *uint32 `json:"version,omitempty" example:"3"`
+	SubscriptionCreateRequest
+}
+
+type BulkUpdateRequest struct {
+	// Mode - atomic (по умолчанию) или best_effort
+	Mode  BulkMode         `json:"mode,omitempty" example:"atomic"`
+	Items []BulkUpdateItem `json:"items"`
+}
+
+type BulkDeleteRequest struct {
+	// Mode - atomic (по умолчанию) или best_effort
+	Mode BulkMode `json:"mode,omitempty" example:"best_effort"`
+	IDs  []uint32 `json:"ids" example:"1,2,3"`
+}
+
+// BulkOutcome - результат обработки одного элемента пакета
+type BulkOutcome struct {
+	// Line - номер строки файла при импорте
+	Line         int
+	ID           uint32
+	Subscription *Subscription
+	Err          error
+}
+
+// BulkResult - результат пакетной операции в порядке элементов запроса
+type BulkResult struct {
+	Mode  BulkMode
+	Items []BulkOutcome
+}
+
+type BulkItemResult struct {
+	// Index - позиция элемента в запросе
+	Index int `json:"index" example:"0"`
+	// Line - номер строки CSV-файла, заполняется при импорте
+	Line int `json:"line,omitempty" example:"2"`
+	// Status - HTTP-статус, который получил бы элемент при отдельном запросе
+	Status       int            `json:"status" example:"201"`
+	ID           uint32         `json:"id,omitempty" example:"1"`
+	Subscription *Subscription  `json:"subscription,omitempty"`
+	Error        *ErrorResponse `json:"error,omitempty"`
+}
+
+type BulkResponse struct {
+	Mode BulkMode `json:"mode" example:"atomic"`
+	// Committed - сохранены ли изменения; в режиме atomic false, если хотя бы один элемент завершился ошибкой
+	Committed bool             `json:"committed" example:"true"`
+	Succeeded int              `json:"succeeded" example:"2"`
+	Failed    int              `json:"failed" example:"0"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// ImportResult - результат импорта подписок из файла
+type ImportResult struct {
+	BulkResult
+	DryRun bool
+}
+
+type ImportResponse struct {
+	// DryRun - файл только проверен, подписки не сохранялись
+	DryRun bool `json:"dry_run" example:"false"`
+	BulkResponse
+}
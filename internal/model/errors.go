@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// Базовые ошибки, по которым обработчики определяют HTTP-статус ответа
+var (
+	ErrNotFound    = errors.New("not found")
+	ErrValidation  = errors.New("validation failed")
+	ErrConflict    = errors.New("conflict")
+	ErrConstraint  = errors.New("constraint violation")
+	ErrUnavailable = errors.New("storage unavailable")
+	// ErrPreconditionFailed возвращается, если версия записи не совпала с ожидаемой клиентом
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrBatchAborted возвращается для элементов атомарного пакета, отмененного из-за ошибки другого элемента
+	ErrBatchAborted = errors.New("batch aborted")
+	// ErrOverflow возвращается, если денежная сумма не помещается в Amount
+	ErrOverflow = errors.New("amount overflow")
+)
+
+// Стабильные коды ошибок API
+const (
+	CodeBadRequest           = "bad_request"
+	CodeTooLarge             = "payload_too_large"
+	CodeNotAcceptable        = "not_acceptable"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeValidation           = "validation_error"
+	CodeNotFound             = "not_found"
+	CodeConflict             = "conflict"
+	CodeConstraint           = "constraint_violation"
+	CodeUnavailable          = "unavailable"
+	CodePrecondition         = "precondition_failed"
+	CodeBatchAborted         = "batch_aborted"
+	CodeOverflow             = "amount_overflow"
+	CodeInternal             = "internal_error"
+)
+
+type ErrorResponse struct {
+	Code      string       `json:"code" example:"validation_error"`
+	Message   string       `json:"message" example:"validation failed: start_date: must be a month in MM-YYYY format"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"request_id,omitempty" example:"3f2b8c1e-6a4d-4e0f-9b7a-2d5c8e1f0a93"`
+}
+
+type FieldError struct {
+	Field   string `json:"field" example:"start_date"`
+	Message string `json:"message" example:"must be a month in MM-YYYY format"`
+}
+
+// ValidationError содержит ошибки отдельных полей запроса
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+
+	return ErrValidation.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// IdempotencyKey описывает ключ идемпотентности запроса на создание подписки
+type IdempotencyKey struct {
+	Key string
+	// RequestHash - отпечаток тела запроса, повтор с тем же ключом и другими данными отклоняется
+	RequestHash string
+	// TTL - время, в течение которого ключ защищает от повторного создания
+	TTL time.Duration
+}
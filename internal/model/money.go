@@ -0,0 +1,143 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Amount - денежная сумма в минимальных единицах валюты: сотых долях рубля, доллара, евро.
+// В JSON кодируется строкой с двумя знаками после точки ("399.90"); при разборе принимается
+// как строка, так и число, в том числе целое число единиц в прежнем формате API
+type Amount int64
+
+// amountScale - количество минимальных единиц в одной единице валюты
+const amountScale = 100
+
+var errAmountFormat = errors.New("must be a decimal number with at most 2 digits after the point")
+
+// ParseAmount разбирает десятичную запись суммы, например "399.9" или "400"
+func ParseAmount(value string) (Amount, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, hasFraction := strings.Cut(value, ".")
+	if !isDigits(whole) || (hasFraction && (!isDigits(fraction) || len(fraction) > 2)) {
+		return 0, errAmountFormat
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/amountScale {
+		return 0, fmt.Errorf("amount %s is too large: %w", value, ErrOverflow)
+	}
+
+	fraction += strings.Repeat("0", 2-len(fraction))
+	cents, _ := strconv.ParseInt(fraction, 10, 64)
+
+	amount := Amount(units*amountScale) + Amount(cents)
+	if amount < 0 {
+		return 0, fmt.Errorf("amount %s is too large: %w", value, ErrOverflow)
+	}
+	if negative {
+		amount = -amount
+	}
+
+	return amount, nil
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String возвращает сумму в десятичной записи с двумя знаками после точки
+func (a Amount) String() string {
+	sign := ""
+	abs := uint64(a)
+	if a < 0 {
+		sign = "-"
+		abs = uint64(-a)
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, abs/amountScale, abs%amountScale)
+}
+
+// Add возвращает a + b или ErrOverflow, если результат не помещается в Amount
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, fmt.Errorf("sum of %s and %s: %w", a, b, ErrOverflow)
+	}
+
+	return sum, nil
+}
+
+// Mul возвращает a * n или ErrOverflow, если результат не помещается в Amount
+func (a Amount) Mul(n int64) (Amount, error) {
+	if a == 0 || n == 0 {
+		return 0, nil
+	}
+
+	product := a * Amount(n)
+	if product/Amount(n) != a || (a == -1 && n == math.MinInt64) || (n == -1 && a == math.MinInt64) {
+		return 0, fmt.Errorf("product of %s and %d: %w", a, n, ErrOverflow)
+	}
+
+	return product, nil
+}
+
+// Prorate возвращает долю used/total суммы, округленную до минимальной единицы (половина - от нуля)
+func (a Amount) Prorate(used, total int64) (Amount, error) {
+	product, err := a.Mul(used)
+	if err != nil {
+		return 0, err
+	}
+
+	quotient, remainder := product/Amount(total), product%Amount(total)
+	switch {
+	case remainder > 0 && 2*int64(remainder) >= total:
+		quotient++
+	case remainder < 0 && -2*int64(remainder) >= total:
+		quotient--
+	}
+
+	return quotient, nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	amount, err := ParseAmount(value)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s: %w", data, err)
+	}
+
+	*a = amount
+	return nil
+}
+
+// Money - сумма вместе с кодом валюты ISO 4217
+type Money struct {
+	Amount   Amount `json:"amount" swaggertype:"string" example:"399.90"`
+	Currency string `json:"currency" example:"RUB"`
+}
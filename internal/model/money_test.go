@@ -0,0 +1,109 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Amount
+		wantErr error
+	}{
+		{name: "whole units", value: "400", want: 40000},
+		{name: "one decimal", value: "399.9", want: 39990},
+		{name: "two decimals", value: "0.05", want: 5},
+		{name: "surrounding spaces", value: " 12.5 ", want: 1250},
+		{name: "negative", value: "-12.34", want: -1234},
+		{name: "max", value: "92233720368547758.07", want: math.MaxInt64},
+		{name: "three decimals", value: "1.234", wantErr: errAmountFormat},
+		{name: "negative with three decimals", value: "-1.234", wantErr: errAmountFormat},
+		{name: "double minus", value: "--1", wantErr: errAmountFormat},
+		{name: "no whole part", value: ".5", wantErr: errAmountFormat},
+		{name: "empty fraction", value: "1.", wantErr: errAmountFormat},
+		{name: "not a number", value: "abc", wantErr: errAmountFormat},
+		{name: "empty", value: "", wantErr: errAmountFormat},
+		{name: "fraction overflows", value: "92233720368547758.08", wantErr: ErrOverflow},
+		{name: "units overflow", value: "92233720368547759", wantErr: ErrOverflow},
+		{name: "too many digits", value: "99999999999999999999", wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAmount(tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseAmount(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseAmount(%q) unexpected error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAmount(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		amount Amount
+		want   string
+	}{
+		{amount: 0, want: "0.00"},
+		{amount: 5, want: "0.05"},
+		{amount: 39990, want: "399.90"},
+		{amount: -1234, want: "-12.34"},
+		{amount: math.MaxInt64, want: "92233720368547758.07"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(tt.amount), got, tt.want)
+		}
+	}
+}
+
+func TestAmountProrate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Amount
+		used    int64
+		total   int64
+		want    Amount
+		wantErr error
+	}{
+		{name: "exact", amount: 39990, used: 1, total: 3, want: 13330},
+		{name: "rounds down", amount: 10000, used: 1, total: 3, want: 3333},
+		{name: "rounds up", amount: 10000, used: 2, total: 3, want: 6667},
+		{name: "half away from zero", amount: 1, used: 1, total: 2, want: 1},
+		{name: "negative half away from zero", amount: -1, used: 1, total: 2, want: -1},
+		{name: "negative rounds down", amount: -10000, used: 1, total: 3, want: -3333},
+		{name: "weeks to month", amount: 10000, used: 52, total: 12, want: 43333},
+		{name: "full period", amount: 3100, used: 31, total: 31, want: 3100},
+		{name: "overflow", amount: math.MaxInt64, used: 2, total: 3, wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.Prorate(tt.used, tt.total)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Prorate error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Prorate unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("%s.Prorate(%d, %d) = %s, want %s", tt.amount, tt.used, tt.total, got, tt.want)
+			}
+		})
+	}
+}
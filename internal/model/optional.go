@@ -0,0 +1,21 @@
+package model
+
+import "encoding/json"
+
+// Optional хранит значение поля JSON и различает три состояния:
+// поле не передано, передано как null и передано со значением
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// PriceChange - цена подписки, действующая с месяца EffectiveFrom до следующего изменения
+type PriceChange struct {
+	EffectiveFrom time.Time `json:"effective_from"`
+	Price         Amount    `json:"price" swaggertype:"string" example:"500.00"`
+}
+
+type PriceChangeRequest struct {
+	// Format: "MM-YYYY", не раньше следующего месяца
+	EffectiveFrom string `json:"effective_from" example:"03-2026" validate:"required,month"`
+	Price         Amount `json:"price" swaggertype:"string" example:"499.90" validate:"required,gt=0,lte=100000000"`
+}
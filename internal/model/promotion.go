@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Promotion - промо-цена подписки, действующая с StartDate по EndDate включительно вместо обычной цены
+type Promotion struct {
+	ID        uint32    `json:"id" example:"1"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	// Цена за период списания во время акции; 0 - бесплатно
+	Price Amount `json:"price" swaggertype:"string" example:"199.00"`
+}
+
+type PromotionRequest struct {
+	// Format: "YYYY-MM-DD" или "MM-YYYY" - с первого числа месяца; не раньше следующего месяца
+	StartDate string `json:"start_date" example:"2025-07-01" validate:"required,date"`
+	// Format: "YYYY-MM-DD" или "MM-YYYY" - по последнее число месяца включительно
+	EndDate string `json:"end_date" example:"2025-09-30" validate:"required,date"`
+	Price   Amount `json:"price" swaggertype:"string" example:"199.00" validate:"gte=0,lte=100000000"`
+}
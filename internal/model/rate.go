@@ -0,0 +1,177 @@
+package model
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultCurrency - валюта подписок без явно указанной валюты и валюта, в которой задаются курсы
+const DefaultCurrency = "RUB"
+
+// ExchangeRate - стоимость единицы валюты в DefaultCurrency, действующая с месяца Month
+// до следующего курса этой валюты
+type ExchangeRate struct {
+	Currency  string    `json:"currency" example:"USD"`
+	Month     time.Time `json:"month"`
+	Rate      Rate      `json:"rate" swaggertype:"number" example:"92.5"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExchangeRateRequest struct {
+	// Код валюты ISO 4217, кроме базовой валюты RUB
+	Currency string `json:"currency" example:"USD" validate:"required,iso4217"`
+	// Format: "MM-YYYY"
+	Month string `json:"month" example:"01-2025" validate:"required,month"`
+	// Стоимость единицы валюты в рублях, не более 10 знаков после точки
+	Rate Rate `json:"rate" swaggertype:"number" example:"92.5" maximum:"100000000" validate:"required,gt=0,lte=1000000000000000000"`
+}
+
+type ExchangeRateImportResponse struct {
+	// Количество загруженных курсов
+	Loaded int `json:"loaded" example:"12"`
+}
+
+// CurrencyTotal - сумма цен подписок в их собственной валюте без пересчета
+type CurrencyTotal struct {
+	Currency  string `json:"currency" example:"USD"`
+	TotalCost Amount `json:"total_cost" swaggertype:"string" example:"120.00"`
+	Months    int32  `json:"months" example:"12"`
+}
+
+// Rate - курс валюты в десятимиллиардных долях, с той же точностью, что и колонка exchange_rates.rate.
+// Хранится целым числом, чтобы пересчет сумм не терял точность на двоичных дробях.
+// В JSON кодируется числом ("92.5"); при разборе принимается как число, так и строка
+type Rate int64
+
+// rateScale - количество долей курса в единице, rateDecimals - количество знаков после точки
+const (
+	rateScale    = 10_000_000_000
+	rateDecimals = 10
+)
+
+// RateOne - курс DefaultCurrency к самой себе
+const RateOne Rate = rateScale
+
+var errRateFormat = fmt.Errorf("must be a decimal number with at most %d digits after the point", rateDecimals)
+
+// ParseRate разбирает десятичную запись курса, например "92.5" или "0.0123"
+func ParseRate(value string) (Rate, error) {
+	value = strings.TrimSpace(value)
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, hasFraction := strings.Cut(value, ".")
+	if !isDigits(whole) || (hasFraction && (!isDigits(fraction) || len(fraction) > rateDecimals)) {
+		return 0, errRateFormat
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/rateScale {
+		return 0, fmt.Errorf("rate %s is too large: %w", value, ErrOverflow)
+	}
+
+	fraction += strings.Repeat("0", rateDecimals-len(fraction))
+	parts, _ := strconv.ParseInt(fraction, 10, 64)
+
+	rate := Rate(units*rateScale) + Rate(parts)
+	if rate < 0 {
+		return 0, fmt.Errorf("rate %s is too large: %w", value, ErrOverflow)
+	}
+	if negative {
+		rate = -rate
+	}
+
+	return rate, nil
+}
+
+// String возвращает курс в десятичной записи без лишних нулей после точки
+func (r Rate) String() string {
+	sign := ""
+	abs := uint64(r)
+	if r < 0 {
+		sign = "-"
+		abs = uint64(-r)
+	}
+
+	whole := strconv.FormatUint(abs/rateScale, 10)
+	fraction := strings.TrimRight(fmt.Sprintf("%0*d", rateDecimals, abs%rateScale), "0")
+	if fraction == "" {
+		return sign + whole
+	}
+
+	return sign + whole + "." + fraction
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	rate, err := ParseRate(value)
+	if err != nil {
+		return fmt.Errorf("invalid rate %s: %w", data, err)
+	}
+
+	*r = rate
+	return nil
+}
+
+// Scan читает курс из колонки NUMERIC без промежуточного float64
+func (r *Rate) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return errors.New("rate must be scanned from NUMERIC")
+	}
+
+	rate, err := ParseRate(value)
+	if err != nil {
+		return fmt.Errorf("invalid rate %s: %w", value, err)
+	}
+
+	*r = rate
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+// Convert пересчитывает сумму по курсам from и to (стоимость единицы исходной и целевой валюты
+// в DefaultCurrency). Пересчет выполняется в целых числах, результат округляется один раз
+// до минимальной единицы (половина - от нуля)
+func (a Amount) Convert(from, to Rate) (Amount, error) {
+	if to <= 0 {
+		return 0, fmt.Errorf("rate %s must be positive", to)
+	}
+
+	product := new(big.Int).Mul(big.NewInt(int64(a)), big.NewInt(int64(from)))
+	divisor := big.NewInt(int64(to))
+
+	quotient, remainder := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	if new(big.Int).Lsh(remainder.Abs(remainder), 1).Cmp(divisor) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(product.Sign())))
+	}
+
+	if !quotient.IsInt64() {
+		return 0, fmt.Errorf("%s converted at %s/%s: %w", a, from, to, ErrOverflow)
+	}
+
+	return Amount(quotient.Int64()), nil
+}
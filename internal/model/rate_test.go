@@ -0,0 +1,95 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func mustParseRate(t *testing.T, value string) Rate {
+	t.Helper()
+
+	rate, err := ParseRate(value)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", value, err)
+	}
+
+	return rate
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Rate
+		wantErr error
+	}{
+		{name: "one", value: "1", want: RateOne},
+		{name: "decimal", value: "92.5", want: 925_000_000_000},
+		{name: "smallest", value: "0.0000000001", want: 1},
+		{name: "max", value: "922337203.6854775807", want: math.MaxInt64},
+		{name: "eleven decimals", value: "0.00000000001", wantErr: errRateFormat},
+		{name: "exponent", value: "1e2", wantErr: errRateFormat},
+		{name: "too large", value: "922337204", wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRate(tt.value)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseRate(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRate(%q) unexpected error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRate(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+			if s := got.String(); s != tt.value {
+				t.Errorf("Rate(%d).String() = %q, want %q", got, s, tt.value)
+			}
+		})
+	}
+}
+
+func TestAmountConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Amount
+		from    string
+		to      string
+		want    Amount
+		wantErr bool
+	}{
+		{name: "same currency", amount: 39990, from: "1", to: "1", want: 39990},
+		{name: "to base currency", amount: 1999, from: "92.5", to: "1", want: 184908},
+		{name: "negative to base currency", amount: -1999, from: "92.5", to: "1", want: -184908},
+		{name: "from base currency", amount: 100000, from: "1", to: "92.5", want: 1081},
+		{name: "cross rate", amount: 1000, from: "92.5", to: "100.3", want: 922},
+		// 0.01 * 2.5 / 5.5 = 0.0045...: при округлении промежуточной суммы до 0.03 вышло бы 0.01
+		{name: "single rounding", amount: 1, from: "2.5", to: "5.5", want: 0},
+		{name: "overflow", amount: math.MaxInt64, from: "2", to: "1", wantErr: true},
+		{name: "zero rate", amount: 100, from: "1", to: "0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.amount.Convert(mustParseRate(t, tt.from), mustParseRate(t, tt.to))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Convert = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("%s.Convert(%s, %s) = %s, want %s", tt.amount, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
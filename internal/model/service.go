@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Service - сервис из каталога, на который оформляются подписки
+type Service struct {
+	ID   uint32 `json:"id" example:"1"`
+	Name string `json:"name" example:"Yandex Plus"`
+	// Aliases - другие написания названия, которые сопоставляются с этим сервисом
+	Aliases      []string `json:"aliases" example:"Яндекс Плюс"`
+	Category     *string  `json:"category,omitempty" example:"music"`
+	DefaultPrice *Amount  `json:"default_price,omitempty" swaggertype:"string" example:"400.00"`
+	// Currency - код валюты ISO 4217
+	Currency  *string   `json:"currency,omitempty" example:"RUB"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ServiceRequest struct {
+	Name         string   `json:"name" example:"Yandex Plus" validate:"required,max=255"`
+	Aliases      []string `json:"aliases,omitempty" example:"Яндекс Плюс" validate:"omitempty,dive,required,max=255"`
+	Category     *string  `json:"category,omitempty" example:"music" validate:"omitempty,max=100"`
+	DefaultPrice *Amount  `json:"default_price,omitempty" swaggertype:"string" example:"399.90" validate:"omitempty,gt=0,lte=100000000"`
+	// Код валюты ISO 4217
+	Currency *string `json:"currency,omitempty" example:"RUB" validate:"omitempty,iso4217"`
+}
+
+type ServiceMergeRequest struct {
+	// SourceID - сервис, который объединяется с текущим и удаляется
+	SourceID uint32 `json:"source_id" example:"2" validate:"required"`
+}
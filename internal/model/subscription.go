@@ -7,25 +7,296 @@ import (
 )
 
 type Subscription struct {
-	ID          uint32     `json:"id" example:"1"`
-	ServiceName string     `json:"service_name" example:"Yandex Plus"`
-	Price       int32      `json:"price" example:"400"`
-	UserID      uuid.UUID  `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
-	StartDate   time.Time  `json:"start_date"`
-	EndDate     *time.Time `json:"end_date,omitempty"`
+	ID          uint32 `json:"id" example:"1"`
+	ServiceID   uint32 `json:"service_id" example:"1"`
+	ServiceName string `json:"service_name" example:"Yandex Plus"`
+	Price       Amount `json:"price" swaggertype:"string" example:"400.00"`
+	Currency    string `json:"currency" example:"RUB"`
+	// Периодичность списаний; цена указана за один период
+	BillingPeriod string `json:"billing_period" example:"monthly" enums:"weekly,monthly,quarterly,yearly,custom"`
+	// Количество месяцев (для weekly - недель) в периоде списания
+	BillingInterval int32 `json:"billing_interval" example:"1"`
+	// День списания: число месяца (в коротких месяцах - последний день), для weekly - день недели (1 - понедельник)
+	AnchorDay int32 `json:"anchor_day" example:"17"`
+	// Количество бесплатных дней пробного периода с даты начала
+	TrialDays int32      `json:"trial_days" example:"14"`
+	UserID    uuid.UUID  `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	StartDate time.Time  `json:"start_date"`
+	EndDate   *time.Time `json:"end_date,omitempty"`
+	// Версия записи, увеличивается при каждом изменении и используется в ETag
+	Version   uint32    `json:"version" example:"1"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Время мягкого удаления; заполнено только у удаленных подписок
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Предупреждения, возникшие при создании (например, пересечение с другой подпиской)
+	Warnings []string `json:"warnings,omitempty"`
+	// Запланированные изменения цены по возрастанию месяца; заполняются только для расчета стоимости
+	PriceChanges []PriceChange `json:"-"`
+	// Промо-цены по возрастанию даты начала; заполняются только для расчета стоимости
+	Promotions []Promotion `json:"-"`
 }
 
+// OverlapPolicy определяет, что делать при создании подписки, пересекающейся по периоду
+// с другой подпиской того же пользователя на тот же сервис
+type OverlapPolicy string
+
+const (
+	// OverlapReject отклоняет создание с ошибкой конфликта
+	OverlapReject OverlapPolicy = "reject"
+	// OverlapMerge расширяет период существующей подписки вместо создания новой
+	OverlapMerge OverlapPolicy = "merge"
+	// OverlapAllow создает подписку и добавляет предупреждение в ответ
+	OverlapAllow OverlapPolicy = "allow"
+)
+
+func (p OverlapPolicy) Valid() bool {
+	return p == OverlapReject || p == OverlapMerge || p == OverlapAllow
+}
+
+// SubscriptionListRequest содержит параметры запроса списка подписок в том виде, в каком они пришли в query
+type SubscriptionListRequest struct {
+	Limit             int32
+	Offset            int32
+	UserID            string
+	ServiceName       string
+	ServiceNamePrefix string
+	MinPrice          string
+	MaxPrice          string
+	ActiveAt          string
+	StartFrom         string
+	StartTo           string
+	EndFrom           string
+	EndTo             string
+	// InTrial оставляет подписки в пробном периоде на текущую дату
+	InTrial bool
+	// TrialEndsWithin - количество дней, в которые должен закончиться текущий пробный период
+	TrialEndsWithin string
+	Sort            string
+	Order           string
+	// Курсор следующей страницы; UseCursor включает keyset-пагинацию даже для первой страницы
+	Cursor    string
+	UseCursor bool
+	WithTotal bool
+	// IncludeDeleted добавляет в выборку мягко удаленные подписки
+	IncludeDeleted bool
+}
+
+// SubscriptionFilter описывает проверенные условия выборки подписок
+type SubscriptionFilter struct {
+	UserID            *uuid.UUID
+	ServiceName       *string
+	ServiceNamePrefix *string
+	MinPrice          *Amount
+	MaxPrice          *Amount
+	ActiveAt          *time.Time
+	StartFrom         *time.Time
+	StartTo           *time.Time
+	EndFrom           *time.Time
+	EndTo             *time.Time
+	// Today - текущая дата для условий пробного периода
+	Today time.Time
+	// InTrial оставляет подписки, пробный период которых идет на дату Today
+	InTrial bool
+	// TrialEndsBy оставляет подписки в пробном периоде на дату Today, последний день которого не позже этой даты
+	TrialEndsBy *time.Time
+	SortBy      string
+	SortDesc    bool
+	Limit       int32
+	Offset      int32
+	// After задает позицию, после которой начинается страница в keyset-пагинации
+	After          *SubscriptionCursor
+	IncludeDeleted bool
+}
+
+// SubscriptionCursor описывает позицию последней записи страницы при keyset-пагинации
+type SubscriptionCursor struct {
+	SortBy   string  `json:"s"`
+	SortDesc bool    `json:"d"`
+	Value    *string `json:"v"`
+	ID       uint32  `json:"id"`
+}
+
+// SubscriptionPage является конвертом ответа списка подписок
+type SubscriptionPage struct {
+	Items []Subscription `json:"items"`
+	// Непрозрачный курсор следующей страницы, null если страниц больше нет
+	NextCursor *string `json:"next_cursor" example:"eyJzIjoiaWQiLCJkIjpmYWxzZSwidiI6IjEwIiwiaWQiOjEwfQ"`
+	Total      *int64  `json:"total,omitempty" example:"42"`
+}
+
+// Поля, по которым допускается сортировка списка подписок
+var SubscriptionSortFields = []string{"id", "service_name", "price", "user_id", "start_date", "end_date"}
+
 type SubscriptionCreateRequest struct {
-	ServiceName string    `json:"service_name" example:"Yandex Plus" validate:"required"`
-	Price       int32     `json:"price" example:"400" validate:"required,gt=0"`
-	UserID      uuid.UUID `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba" validate:"required"`
-	StartDate   string    `json:"start_date" example:"07-2025" validate:"required"`
-	EndDate     *string   `json:"end_date,omitempty" example:"12-2025"`
+	// ServiceID - сервис из каталога; если задан, service_name не учитывается
+	ServiceID *uint32 `json:"service_id,omitempty" example:"1"`
+	// ServiceName - название или синоним сервиса; неизвестное название добавляется в каталог
+	ServiceName string `json:"service_name,omitempty" example:"Yandex Plus" validate:"max=255"`
+	// Цена в единицах валюты с точностью до сотых: строка "399.90" или число
+	Price Amount `json:"price" swaggertype:"string" example:"399.90" validate:"required,gt=0,lte=100000000"`
+	// Currency - код валюты ISO 4217; по умолчанию валюта сервиса из каталога или RUB
+	Currency *string `json:"currency,omitempty" example:"RUB" validate:"omitempty,iso4217"`
+	// BillingPeriod - периодичность списаний, по умолчанию monthly; price - цена одного периода
+	BillingPeriod *string `json:"billing_period,omitempty" example:"monthly" validate:"omitempty,oneof=weekly monthly quarterly yearly custom"`
+	// BillingInterval - количество месяцев в периоде, задается только для billing_period=custom
+	BillingInterval *int32 `json:"billing_interval,omitempty" example:"6" validate:"omitempty,gte=1,lte=120"`
+	// AnchorDay - день списания, по умолчанию день даты начала. Первое списание приходится на дату начала,
+	// следующие - на этот день каждого периода
+	AnchorDay *int32 `json:"anchor_day,omitempty" example:"17" validate:"omitempty,gte=1,lte=31"`
+	// TrialDays - длина бесплатного пробного периода в днях; списания в нем не оплачиваются
+	TrialDays int32     `json:"trial_days,omitempty" example:"14" validate:"gte=0,lte=365"`
+	UserID    uuid.UUID `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba" validate:"required"`
+	// Format: "YYYY-MM-DD" или "MM-YYYY" - с первого числа месяца
+	StartDate string `json:"start_date" example:"2025-07-17" validate:"required,date"`
+	// Format: "YYYY-MM-DD" или "MM-YYYY" - по последнее число месяца включительно
+	EndDate *string `json:"end_date,omitempty" example:"2025-12-16" validate:"omitempty,date"`
+}
+
+// SubscriptionPatchRequest описывает частичное обновление подписки по семантике JSON Merge Patch (RFC 7396):
+// отсутствующие поля не меняются, null очищает значение
+type SubscriptionPatchRequest struct {
+	ServiceID   Optional[uint32] `json:"service_id" swaggertype:"integer" example:"1"`
+	ServiceName Optional[string] `json:"service_name" swaggertype:"string" example:"Yandex Plus"`
+	Price       Optional[Amount] `json:"price" swaggertype:"string" example:"399.90"`
+	Currency    Optional[string] `json:"currency" swaggertype:"string" example:"RUB"`
+	// Изменение периодичности сбрасывает интервал и день списания, если они не переданы вместе с ней
+	BillingPeriod Optional[string] `json:"billing_period" swaggertype:"string" example:"yearly"`
+	// Количество месяцев в периоде для billing_period=custom
+	BillingInterval Optional[int32] `json:"billing_interval" swaggertype:"integer" example:"6"`
+	// null возвращает день списания по умолчанию - день даты начала
+	AnchorDay Optional[int32]     `json:"anchor_day" swaggertype:"integer" example:"17"`
+	TrialDays Optional[int32]     `json:"trial_days" swaggertype:"integer" example:"14"`
+	UserID    Optional[uuid.UUID] `json:"user_id" swaggertype:"string" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	// Format: "YYYY-MM-DD" или "MM-YYYY"
+	StartDate Optional[string] `json:"start_date" swaggertype:"string" example:"2025-07-17"`
+	// Format: "YYYY-MM-DD" или "MM-YYYY", null отменяет дату окончания
+	EndDate Optional[string] `json:"end_date" swaggertype:"string" example:"2025-12-16"`
+}
+
+// SubscriptionPatch содержит проверенные изменения подписки; nil-поля не изменяются
+type SubscriptionPatch struct {
+	// ServiceID и ServiceName задают новый сервис; ID имеет приоритет над названием
+	ServiceID   *uint32
+	ServiceName *string
+	Price       *Amount
+	Currency    *string
+	// Периодичность, интервал и день списания записываются вместе
+	BillingPeriod   *string
+	BillingInterval *int32
+	AnchorDay       *int32
+	TrialDays       *int32
+	UserID          *uuid.UUID
+	StartDate       *time.Time
+	// EndDateSet показывает, что дату окончания нужно записать, в том числе NULL
+	EndDateSet bool
+	EndDate    *time.Time
+}
+
+// IsEmpty сообщает, что изменений нет и записывать нечего
+func (p SubscriptionPatch) IsEmpty() bool {
+	return p.ServiceID == nil && p.ServiceName == nil && p.Price == nil && p.Currency == nil && p.BillingPeriod == nil && p.TrialDays == nil && p.UserID == nil && p.StartDate == nil && !p.EndDateSet
 }
 
 type TotalCostRequest struct {
-	StartDate   string     `json:"start_date" example:"01-2025" validate:"required"`
-	EndDate     string     `json:"end_date" example:"12-2025" validate:"required"`
+	StartDate   string     `json:"start_date" example:"01-2025" validate:"required,month"`
+	EndDate     string     `json:"end_date" example:"12-2025" validate:"required,month"`
 	UserID      *uuid.UUID `json:"user_id,omitempty" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	ServiceName *string    `json:"service_name,omitempty" example:"Yandex Plus" validate:"omitempty,max=255"`
+	// Валюта результата (ISO 4217), по умолчанию RUB. Цены в других валютах пересчитываются по курсу каждого месяца
+	Currency *string `json:"currency,omitempty" example:"RUB" validate:"omitempty,iso4217"`
+	// Proration - неполные месяцы подписки оплачиваются пропорционально дням использования, а не целиком
+	Proration bool `json:"proration,omitempty" example:"false"`
+}
+
+type TotalCostResponse struct {
+	TotalCost Amount `json:"total_cost" swaggertype:"string" example:"4800.00"`
+	Currency  string `json:"currency" example:"RUB"`
+	// Суммарное количество оплачиваемых месяцев по всем подпискам периода
+	Months int32 `json:"months" example:"12"`
+	// Суммы в исходных валютах подписок без пересчета
+	ByCurrency []CurrencyTotal `json:"by_currency"`
+}
+
+// Допустимые значения группировки в отчетах по стоимости
+const (
+	GroupByServiceName = "service_name"
+	GroupByUserID      = "user_id"
+)
+
+type CostBreakdownRequest struct {
+	TotalCostRequest
+	// Дополнительная группировка внутри месяца: "service_name" или "user_id"
+	GroupBy *string `json:"group_by,omitempty" example:"service_name" enums:"service_name,user_id"`
+}
+
+type CostBreakdownResponse struct {
+	Months    []MonthlyCost `json:"months"`
+	TotalCost Amount        `json:"total_cost" swaggertype:"string" example:"4800.00"`
+	Currency  string        `json:"currency" example:"RUB"`
+}
+
+type MonthlyCost struct {
+	// Format: "YYYY-MM"
+	Month     string      `json:"month" example:"2025-01"`
+	TotalCost Amount      `json:"total_cost" swaggertype:"string" example:"1200.00"`
+	Groups    []GroupCost `json:"groups,omitempty"`
+}
+
+type GroupCost struct {
+	Key       string `json:"key" example:"Yandex Plus"`
+	TotalCost Amount `json:"total_cost" swaggertype:"string" example:"400.00"`
+}
+
+// Допустимые значения сортировки отчета о расходах
+const (
+	SortByTotalCost = "total_cost"
+	SortByKey       = "key"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+type SpendReportRequest struct {
+	TotalCostRequest
+	// Поля группировки: "service_name", "user_id" или оба
+	GroupBy []string `json:"group_by" example:"service_name" validate:"required"`
+	// Поле сортировки: "total_cost" (по умолчанию) или "key"
+	SortBy *string `json:"sort_by,omitempty" example:"total_cost" enums:"total_cost,key"`
+	// Направление сортировки: "desc" (по умолчанию) или "asc"
+	Order *string `json:"order,omitempty" example:"desc" enums:"asc,desc"`
+	// Количество групп в ответе (top-N), без ограничения если не задано
+	Limit *int32 `json:"limit,omitempty" example:"5"`
+}
+
+type SpendReportResponse struct {
+	Groups    []SpendGroup `json:"groups"`
+	TotalCost Amount       `json:"total_cost" swaggertype:"string" example:"4800.00"`
+	Currency  string       `json:"currency" example:"RUB"`
+}
+
+type SpendGroup struct {
 	ServiceName *string    `json:"service_name,omitempty" example:"Yandex Plus"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	TotalCost   Amount     `json:"total_cost" swaggertype:"string" example:"4800.00"`
+	Months      int32      `json:"months" example:"12"`
+}
+
+// Статусы подписки относительно текущего месяца
+const (
+	StatusActive   = "active"
+	StatusExpired  = "expired"
+	StatusUpcoming = "upcoming"
+)
+
+type UserSubscription struct {
+	Subscription
+	Status string `json:"status" example:"active" enums:"active,expired,upcoming"`
+}
+
+type UserSubscriptionsResponse struct {
+	UserID        uuid.UUID          `json:"user_id" example:"60601fee-2bf1-4721-ae6f-7636e79a0cba"`
+	Subscriptions []UserSubscription `json:"subscriptions"`
+	// Месячные расходы на активные подписки в валюте Currency; цены других периодичностей приводятся к месяцу
+	MonthlySpend Amount `json:"monthly_spend" swaggertype:"string" example:"1200.00"`
+	Currency     string `json:"currency" example:"RUB"`
 }
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// writeAudit добавляет запись в журнал изменений в той же транзакции, что и само изменение.
+// before и after - снимки подписки до и после изменения, nil означает отсутствие снимка
+func writeAudit(tx *sql.Tx, action model.AuditAction, id uint32, before, after *model.Subscription, meta model.AuditMeta) error {
+	beforeJSON, err := auditSnapshot(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := auditSnapshot(after)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO subscription_audit_log (subscription_id, action, before, after, actor, request_id) 
+	          VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''))`
+
+	_, err = tx.Exec(query, id, action, beforeJSON, afterJSON, meta.Actor, meta.RequestID)
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// auditRecord - снимок подписки в журнале. Изменения цены и акции попадают в снимок,
+// только если были загружены вместе с подпиской
+type auditRecord struct {
+	model.Subscription
+	PriceChanges []model.PriceChange `json:"price_changes,omitempty"`
+	Promotions   []model.Promotion   `json:"promotions,omitempty"`
+}
+
+// auditSnapshot сериализует подписку для журнала без предупреждений, которые не хранятся в базе
+func auditSnapshot(sub *model.Subscription) ([]byte, error) {
+	if sub == nil {
+		return nil, nil
+	}
+
+	snapshot := auditRecord{Subscription: *sub, PriceChanges: sub.PriceChanges, Promotions: sub.Promotions}
+	snapshot.Warnings = nil
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit snapshot: %w", err)
+	}
+
+	return data, nil
+}
+
+// ListAudit возвращает журнал изменений подписки в порядке их выполнения
+func (r *subscriptionRepository) ListAudit(subscriptionID uint32) ([]model.AuditEntry, error) {
+	query := `SELECT id, subscription_id, action, before, after, 
+	                 COALESCE(actor, ''), COALESCE(request_id, ''), created_at 
+	          FROM subscription_audit_log 
+	          WHERE subscription_id = $1 
+	          ORDER BY id`
+
+	rows, err := r.db.Query(query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var entry model.AuditEntry
+		var before, after []byte
+		err := rows.Scan(&entry.ID, &entry.SubscriptionID, &entry.Action, &before, &after,
+			&entry.Actor, &entry.RequestID, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", mapError(err))
+		}
+		if before != nil {
+			entry.Before = json.RawMessage(before)
+		}
+		if after != nil {
+			entry.After = json.RawMessage(after)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate audit log: %w", mapError(err))
+	}
+
+	return entries, nil
+}
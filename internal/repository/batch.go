@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// runBatch выполняет fn для каждого из n элементов пакета в одной транзакции. Каждый элемент
+// изолирован точкой сохранения, поэтому ошибка элемента отменяет только его изменения.
+// В атомарном режиме транзакция фиксируется, только если все элементы выполнены успешно.
+// Возвращает ошибки элементов по их позициям; общая ошибка означает, что не сохранен ни один элемент
+func (r *subscriptionRepository) runBatch(n int, atomic bool, fn func(tx *sql.Tx, i int) error) ([]error, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", mapError(err))
+	}
+	defer tx.Rollback()
+
+	errs := make([]error, n)
+	failed := 0
+
+	for i := 0; i < n; i++ {
+		if _, err := tx.Exec("SAVEPOINT batch_item"); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", mapError(err))
+		}
+
+		if err := fn(tx, i); err != nil {
+			errs[i] = err
+			failed++
+			if _, err := tx.Exec("ROLLBACK TO SAVEPOINT batch_item"); err != nil {
+				return nil, fmt.Errorf("failed to roll back batch item: %w", mapError(err))
+			}
+			continue
+		}
+
+		if _, err := tx.Exec("RELEASE SAVEPOINT batch_item"); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", mapError(err))
+		}
+	}
+
+	if atomic && failed > 0 {
+		return errs, nil
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	log.Printf("Applied batch of %d subscription changes, %d failed", n, failed)
+	return errs, nil
+}
+
+// CreateBatch создает подписки пакетом с проверкой пересечений по политике policy
+func (r *subscriptionRepository) CreateBatch(subs []*model.Subscription, policy model.OverlapPolicy, meta model.AuditMeta, atomic bool) ([]error, error) {
+	return r.runBatch(len(subs), atomic, func(tx *sql.Tx, i int) error {
+		return createWithPolicy(tx, subs[i], policy, meta)
+	})
+}
+
+// UpdateBatch перезаписывает подписки пакетом с проверкой пересечений по политике policy.
+// expectedVersions[i] задает ожидаемую версию subs[i]
+func (r *subscriptionRepository) UpdateBatch(subs []*model.Subscription, expectedVersions []*uint32, policy model.OverlapPolicy, meta model.AuditMeta, atomic bool) ([]error, error) {
+	return r.runBatch(len(subs), atomic, func(tx *sql.Tx, i int) error {
+		return updateSubscription(tx, subs[i], expectedVersions[i], policy, meta)
+	})
+}
+
+// DeleteBatch помечает подписки удаленными пакетом
+func (r *subscriptionRepository) DeleteBatch(ids []uint32, meta model.AuditMeta, atomic bool) ([]error, error) {
+	return r.runBatch(len(ids), atomic, func(tx *sql.Tx, i int) error {
+		return deleteSubscription(tx, ids[i], nil, meta)
+	})
+}
@@ -0,0 +1,517 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const serviceColumns = "s.id, s.name, s.category, s.default_price, s.currency, s.created_at, s.updated_at"
+
+type catalogRepository struct {
+	db *sql.DB
+}
+
+func NewCatalogRepository(db *sql.DB) *catalogRepository {
+	return &catalogRepository{db: db}
+}
+
+func (r *catalogRepository) Create(svc *model.Service) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", mapError(err))
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO services (name, category, default_price, currency) 
+	          VALUES ($1, $2, $3, $4) RETURNING id, created_at, updated_at`
+
+	err = tx.QueryRow(query, svc.Name, svc.Category, svc.DefaultPrice, svc.Currency).
+		Scan(&svc.ID, &svc.CreatedAt, &svc.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert service: %w", mapError(err))
+	}
+
+	if err := writeAliases(tx, svc); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	log.Printf("Created service %q with ID: %d", svc.Name, svc.ID)
+	return nil
+}
+
+func (r *catalogRepository) GetByID(id uint32) (*model.Service, error) {
+	return getService(r.db, id)
+}
+
+// List возвращает сервисы каталога по названию, при заданной категории - только из нее
+func (r *catalogRepository) List(category *string) ([]model.Service, error) {
+	query := `SELECT ` + serviceColumns + `, array_remove(array_agg(a.alias ORDER BY a.alias), NULL) 
+	          FROM services s LEFT JOIN service_aliases a ON a.service_id = s.id 
+	          WHERE ($1::text IS NULL OR s.category = $1) 
+	          GROUP BY s.id 
+	          ORDER BY s.name, s.id`
+
+	rows, err := r.db.Query(query, category)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	services := []model.Service{}
+	for rows.Next() {
+		svc, err := scanService(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", mapError(err))
+		}
+		services = append(services, *svc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating services: %w", mapError(err))
+	}
+
+	return services, nil
+}
+
+// Update перезаписывает сервис и его синонимы. При переименовании название обновляется
+// и в подписках сервиса, их версия увеличивается, а изменение записывается в журнал
+func (r *catalogRepository) Update(svc *model.Service, meta model.AuditMeta) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", mapError(err))
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE services 
+	          SET name = $1, category = $2, default_price = $3, currency = $4, updated_at = now() 
+	          WHERE id = $5 
+	          RETURNING created_at, updated_at`
+
+	err = tx.QueryRow(query, svc.Name, svc.Category, svc.DefaultPrice, svc.Currency, svc.ID).
+		Scan(&svc.CreatedAt, &svc.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("service with ID %d %w", svc.ID, model.ErrNotFound)
+		}
+		return fmt.Errorf("failed to update service: %w", mapError(err))
+	}
+
+	if _, err := tx.Exec("DELETE FROM service_aliases WHERE service_id = $1", svc.ID); err != nil {
+		return fmt.Errorf("failed to delete service aliases: %w", mapError(err))
+	}
+
+	if err := writeAliases(tx, svc); err != nil {
+		return err
+	}
+
+	if err := moveSubscriptions(tx, svc.ID, svc.ID, svc.Name, nil, meta); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	log.Printf("Updated service with ID: %d", svc.ID)
+	return nil
+}
+
+// Delete удаляет сервис, если на него не оформлено ни одной подписки, в том числе удаленной
+func (r *catalogRepository) Delete(id uint32) error {
+	query := `DELETE FROM services 
+	          WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM subscriptions WHERE service_id = $1)`
+
+	result, err := r.db.Exec(query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service: %w", mapError(err))
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", mapError(err))
+	}
+
+	if deleted == 0 {
+		if _, err := r.GetByID(id); err != nil {
+			return err
+		}
+		return fmt.Errorf("service with ID %d is used by subscriptions: %w", id, model.ErrConflict)
+	}
+
+	log.Printf("Deleted service with ID: %d", id)
+	return nil
+}
+
+// Merge переносит подписки и синонимы сервиса sourceID в сервис targetID и удаляет sourceID.
+// Название исходного сервиса становится синонимом целевого
+func (r *catalogRepository) Merge(targetID, sourceID uint32, meta model.AuditMeta) (*model.Service, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", mapError(err))
+	}
+	defer tx.Rollback()
+
+	// Пары пользователь/сервис блокируются раньше строк, как при создании и изменении подписок
+	users, err := lockMergedPairs(tx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Блокируем оба сервиса в порядке ID, чтобы встречные объединения не взаимоблокировались
+	rows, err := tx.Query("SELECT id, name FROM services WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock services: %w", mapError(err))
+	}
+
+	names := make(map[uint32]string, 2)
+	for rows.Next() {
+		var id uint32
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan service: %w", mapError(err))
+		}
+		names[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating services: %w", mapError(err))
+	}
+
+	for _, id := range []uint32{targetID, sourceID} {
+		if _, ok := names[id]; !ok {
+			return nil, fmt.Errorf("service with ID %d %w", id, model.ErrNotFound)
+		}
+	}
+
+	if _, err := tx.Exec("UPDATE service_aliases SET service_id = $1 WHERE service_id = $2", targetID, sourceID); err != nil {
+		return nil, fmt.Errorf("failed to move service aliases: %w", mapError(err))
+	}
+
+	if err := moveSubscriptions(tx, sourceID, targetID, names[targetID], users, meta); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec("DELETE FROM services WHERE id = $1", sourceID); err != nil {
+		return nil, fmt.Errorf("failed to delete merged service: %w", mapError(err))
+	}
+
+	if _, err := tx.Exec("UPDATE services SET updated_at = now() WHERE id = $1", targetID); err != nil {
+		return nil, fmt.Errorf("failed to update service: %w", mapError(err))
+	}
+
+	svc, err := getService(tx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	log.Printf("Merged service %d into service %d", sourceID, targetID)
+	return svc, nil
+}
+
+// getService читает сервис с синонимами через *sql.DB или *sql.Tx
+func getService(q queryRower, id uint32) (*model.Service, error) {
+	query := `SELECT ` + serviceColumns + `, array_remove(array_agg(a.alias ORDER BY a.alias), NULL) 
+	          FROM services s LEFT JOIN service_aliases a ON a.service_id = s.id 
+	          WHERE s.id = $1 
+	          GROUP BY s.id`
+
+	svc, err := scanService(q.QueryRow(query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("service with ID %d %w", id, model.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get service: %w", mapError(err))
+	}
+
+	return svc, nil
+}
+
+// scanService читает сервис, выбранный с колонками serviceColumns и массивом всех написаний.
+// Написание, совпадающее с названием, в синонимы не попадает
+func scanService(row rowScanner) (*model.Service, error) {
+	var svc model.Service
+	var category, currency sql.NullString
+	var defaultPrice sql.NullInt64
+	var names []string
+
+	err := row.Scan(&svc.ID, &svc.Name, &category, &defaultPrice, &currency, &svc.CreatedAt, &svc.UpdatedAt, pq.Array(&names))
+	if err != nil {
+		return nil, err
+	}
+
+	if category.Valid {
+		svc.Category = &category.String
+	}
+	if defaultPrice.Valid {
+		price := model.Amount(defaultPrice.Int64)
+		svc.DefaultPrice = &price
+	}
+	if currency.Valid {
+		svc.Currency = &currency.String
+	}
+
+	svc.Aliases = []string{}
+	for _, name := range names {
+		if serviceKey(name) != serviceKey(svc.Name) {
+			svc.Aliases = append(svc.Aliases, name)
+		}
+	}
+
+	return &svc, nil
+}
+
+// writeAliases сохраняет название и синонимы сервиса. Написания, совпадающие по ключу, сохраняются один раз,
+// и в svc.Aliases остаются только сохраненные синонимы; написание, занятое другим сервисом, приводит к ErrConflict
+func writeAliases(tx *sql.Tx, svc *model.Service) error {
+	seen := make(map[string]bool, len(svc.Aliases)+1)
+	aliases := []string{}
+
+	for i, alias := range append([]string{svc.Name}, svc.Aliases...) {
+		key := serviceKey(alias)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if i > 0 {
+			aliases = append(aliases, alias)
+		}
+
+		_, err := tx.Exec("INSERT INTO service_aliases (alias_key, alias, service_id) VALUES ($1, $2, $3)", key, alias, svc.ID)
+		if err != nil {
+			err = mapError(err)
+			if errors.Is(err, model.ErrConflict) {
+				return fmt.Errorf("name %q is already used by another service: %w", alias, err)
+			}
+			return fmt.Errorf("failed to insert service alias: %w", err)
+		}
+	}
+
+	svc.Aliases = aliases
+	return nil
+}
+
+// lockMergedPairs блокирует пары пользователь/сервис для всех пользователей подписок сервиса sourceID:
+// их подписки переходят на сервис targetID. Блокировки берутся в порядке пользователей и сервисов,
+// чтобы встречные объединения не взаимоблокировались. Возвращает пользователей с заблокированными парами
+func lockMergedPairs(tx *sql.Tx, sourceID, targetID uint32) (map[uuid.UUID]bool, error) {
+	rows, err := tx.Query("SELECT DISTINCT user_id FROM subscriptions WHERE service_id = $1 ORDER BY user_id", sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service users: %w", mapError(err))
+	}
+
+	var userIDs []uuid.UUID
+	for rows.Next() {
+		var userID uuid.UUID
+		if err := rows.Scan(&userID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan service user: %w", mapError(err))
+		}
+		userIDs = append(userIDs, userID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating service users: %w", mapError(err))
+	}
+
+	first, second := min(sourceID, targetID), max(sourceID, targetID)
+	users := make(map[uuid.UUID]bool, len(userIDs))
+	for _, userID := range userIDs {
+		for _, serviceID := range []uint32{first, second} {
+			if err := lockSubscriptionPair(tx, userID, serviceID); err != nil {
+				return nil, err
+			}
+		}
+		users[userID] = true
+	}
+
+	return users, nil
+}
+
+// checkMergeOverlaps проверяет, что переводимые на сервис toID подписки не пересекаются
+// с подписками того же пользователя на этом сервисе. Пересечения возвращаются ошибкой
+// конфликта со списком ID подписок
+func checkMergeOverlaps(tx *sql.Tx, moved []model.Subscription, toID uint32) error {
+	var conflicts []string
+	for _, sub := range moved {
+		if sub.DeletedAt != nil {
+			continue
+		}
+
+		sub.ServiceID = toID
+		overlapping, err := findOverlapping(tx, &sub)
+		if err != nil {
+			return err
+		}
+		for _, existing := range overlapping {
+			conflicts = append(conflicts, fmt.Sprintf("%d with %d", sub.ID, existing.ID))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("merged subscriptions overlap with subscriptions of the target service (%s): %w",
+			strings.Join(conflicts, ", "), model.ErrConflict)
+	}
+
+	return nil
+}
+
+// moveSubscriptions переводит подписки сервиса fromID на сервис toID с названием name
+// и фиксирует изменение каждой подписки в журнале в той же транзакции.
+// При переводе на другой сервис пары пользователей users должны быть заблокированы lockMergedPairs,
+// а пересечения периодов с подписками сервиса toID отклоняются
+func moveSubscriptions(tx *sql.Tx, fromID, toID uint32, name string, users map[uuid.UUID]bool, meta model.AuditMeta) error {
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions 
+	          WHERE service_id = $1 AND (service_id <> $2 OR service_name <> $3) 
+	          ORDER BY id 
+	          FOR UPDATE`
+
+	rows, err := tx.Query(query, fromID, toID, name)
+	if err != nil {
+		return fmt.Errorf("failed to lock service subscriptions: %w", mapError(err))
+	}
+
+	before, err := scanSubscriptions(rows)
+	if err != nil {
+		return err
+	}
+	if len(before) == 0 {
+		return nil
+	}
+
+	if fromID != toID {
+		for _, sub := range before {
+			if !users[sub.UserID] {
+				return fmt.Errorf("subscriptions of service %d were modified concurrently: %w", fromID, model.ErrConflict)
+			}
+		}
+		if err := checkMergeOverlaps(tx, before, toID); err != nil {
+			return err
+		}
+	}
+
+	ids := make([]int64, len(before))
+	for i, sub := range before {
+		ids[i] = int64(sub.ID)
+	}
+
+	query = `UPDATE subscriptions 
+	         SET service_id = $1, service_name = $2, version = version + 1, updated_at = now() 
+	         WHERE id = ANY($3) 
+	         RETURNING ` + subscriptionColumns
+
+	rows, err = tx.Query(query, toID, name, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to move service subscriptions: %w", mapError(err))
+	}
+
+	after, err := scanSubscriptions(rows)
+	if err != nil {
+		return err
+	}
+
+	updated := make(map[uint32]*model.Subscription, len(after))
+	for i := range after {
+		updated[after[i].ID] = &after[i]
+	}
+
+	for i := range before {
+		if err := writeAudit(tx, model.AuditCatalog, before[i].ID, &before[i], updated[before[i].ID], meta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveService определяет сервис подписки: по sub.ServiceID, если он задан, иначе по названию
+// или синониму в sub.ServiceName. Неизвестное название добавляется в каталог как новый сервис.
+// В sub записываются ID и каноническое название сервиса, а если валюта не задана - валюта сервиса
+// или DefaultCurrency
+func resolveService(tx *sql.Tx, sub *model.Subscription) error {
+	currency, err := findService(tx, sub)
+	if err != nil {
+		return err
+	}
+
+	if sub.Currency == "" {
+		sub.Currency = model.DefaultCurrency
+		if currency.Valid {
+			sub.Currency = currency.String
+		}
+	}
+
+	return nil
+}
+
+// findService находит или создает сервис подписки и возвращает его валюту
+func findService(tx *sql.Tx, sub *model.Subscription) (sql.NullString, error) {
+	var currency sql.NullString
+
+	if sub.ServiceID != 0 {
+		err := tx.QueryRow("SELECT name, currency FROM services WHERE id = $1", sub.ServiceID).Scan(&sub.ServiceName, &currency)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return currency, model.NewValidationError("service_id", fmt.Sprintf("service %d does not exist", sub.ServiceID))
+			}
+			return currency, fmt.Errorf("failed to get service: %w", mapError(err))
+		}
+		return currency, nil
+	}
+
+	name := strings.Join(strings.Fields(sub.ServiceName), " ")
+	key := serviceKey(name)
+
+	// Параллельные создания подписок на новый сервис не должны добавить его в каталог дважды
+	if _, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext('service/' || $1))", key); err != nil {
+		return currency, fmt.Errorf("failed to lock service name: %w", mapError(err))
+	}
+
+	query := `SELECT s.id, s.name, s.currency 
+	          FROM service_aliases a JOIN services s ON s.id = a.service_id 
+	          WHERE a.alias_key = $1`
+
+	err := tx.QueryRow(query, key).Scan(&sub.ServiceID, &sub.ServiceName, &currency)
+	if err == nil {
+		return currency, nil
+	}
+	if err != sql.ErrNoRows {
+		return currency, fmt.Errorf("failed to resolve service: %w", mapError(err))
+	}
+
+	svc := &model.Service{Name: name}
+	err = tx.QueryRow("INSERT INTO services (name) VALUES ($1) RETURNING id", name).Scan(&svc.ID)
+	if err != nil {
+		return currency, fmt.Errorf("failed to insert service: %w", mapError(err))
+	}
+
+	if err := writeAliases(tx, svc); err != nil {
+		return currency, err
+	}
+
+	log.Printf("Added service %q to catalog with ID: %d", name, svc.ID)
+	sub.ServiceID = svc.ID
+	sub.ServiceName = svc.Name
+	return currency, nil
+}
+
+// serviceKey приводит написание названия к ключу сопоставления: нижний регистр и одиночные пробелы
+func serviceKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "github.com/Fedasov/Effective-Mobile/internal/model"
+
+type CatalogRepository interface {
+	Create(svc *model.Service) error
+	GetByID(id uint32) (*model.Service, error)
+	List(category *string) ([]model.Service, error)
+	Update(svc *model.Service, meta model.AuditMeta) error
+	Delete(id uint32) error
+	Merge(targetID, sourceID uint32, meta model.AuditMeta) (*model.Service, error)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/lib/pq"
+)
+
+// mapError переводит ошибку базы данных в одну из типизированных ошибок модели.
+// Для нарушений ограничений наружу отдается только имя ограничения, исходный текст пишется в лог
+func mapError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code == "23505" || pqErr.Code == "23P01":
+			log.Printf("Database conflict: %v", err)
+			return fmt.Errorf("%w: %s", model.ErrConflict, constraintName(pqErr))
+		case pqErr.Code.Class() == "23":
+			log.Printf("Database constraint violation: %v", err)
+			return fmt.Errorf("%w: %s", model.ErrConstraint, constraintName(pqErr))
+		case pqErr.Code.Class() == "08" || pqErr.Code.Class() == "53" || pqErr.Code.Class() == "57":
+			return fmt.Errorf("%w: %w", model.ErrUnavailable, err)
+		}
+
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", model.ErrUnavailable, err)
+	}
+
+	return err
+}
+
+// constraintName возвращает имя нарушенного ограничения или его описание из кода ошибки
+func constraintName(pqErr *pq.Error) string {
+	if pqErr.Constraint != "" {
+		return pqErr.Constraint
+	}
+
+	return pqErr.Code.Name()
+}
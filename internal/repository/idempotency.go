@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// CreateIdempotent создает подписку и сохраняет результат под ключом идемпотентности в одной транзакции.
+// Если ключ уже использован и не истек, sub заполняется сохраненной подпиской и возвращается replayed = true.
+// Параллельные запросы с одним ключом ждут на уникальном индексе, пока первый не завершится
+func (r *subscriptionRepository) CreateIdempotent(sub *model.Subscription, key model.IdempotencyKey, policy model.OverlapPolicy, meta model.AuditMeta) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", mapError(err))
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM idempotency_keys WHERE key = $1 AND created_at < $2", key.Key, time.Now().Add(-key.TTL))
+	if err != nil {
+		return false, fmt.Errorf("failed to expire idempotency key: %w", mapError(err))
+	}
+
+	result, err := tx.Exec(`INSERT INTO idempotency_keys (key, request_hash) VALUES ($1, $2) 
+	                        ON CONFLICT (key) DO NOTHING`, key.Key, key.RequestHash)
+	if err != nil {
+		return false, fmt.Errorf("failed to store idempotency key: %w", mapError(err))
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", mapError(err))
+	}
+
+	if inserted == 0 {
+		if err := replayIdempotent(tx, sub, key); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if err := createWithPolicy(tx, sub, policy, meta); err != nil {
+		return false, err
+	}
+
+	body, err := json.Marshal(sub)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode idempotent response: %w", err)
+	}
+
+	_, err = tx.Exec("UPDATE idempotency_keys SET subscription_id = $1, response_body = $2 WHERE key = $3", sub.ID, body, key.Key)
+	if err != nil {
+		return false, fmt.Errorf("failed to store idempotent response: %w", mapError(err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	return false, nil
+}
+
+// replayIdempotent читает сохраненный ответ по ключу и проверяет, что запрос совпадает с исходным
+func replayIdempotent(tx *sql.Tx, sub *model.Subscription, key model.IdempotencyKey) error {
+	var requestHash string
+	var body []byte
+
+	err := tx.QueryRow("SELECT request_hash, response_body FROM idempotency_keys WHERE key = $1", key.Key).Scan(&requestHash, &body)
+	if err != nil {
+		return fmt.Errorf("failed to read idempotency key: %w", mapError(err))
+	}
+
+	if requestHash != key.RequestHash {
+		return fmt.Errorf("idempotency key %q was used with a different request: %w", key.Key, model.ErrConflict)
+	}
+
+	if err := json.Unmarshal(body, sub); err != nil {
+		return fmt.Errorf("failed to decode idempotent response: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeIdempotencyKeys удаляет ключи идемпотентности, созданные раньше указанного момента
+func (r *subscriptionRepository) PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM idempotency_keys WHERE created_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge idempotency keys: %w", mapError(err))
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", mapError(err))
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/google/uuid"
+)
+
+// createWithPolicy добавляет подписку в транзакции tx с проверкой пересечения периодов
+// с подписками того же пользователя на тот же сервис. Конкурентные создания для одной пары
+// пользователь/сервис сериализуются advisory-блокировкой до конца транзакции
+func createWithPolicy(tx *sql.Tx, sub *model.Subscription, policy model.OverlapPolicy, meta model.AuditMeta) error {
+	if err := resolveService(tx, sub); err != nil {
+		return err
+	}
+
+	if err := lockSubscriptionPair(tx, sub.UserID, sub.ServiceID); err != nil {
+		return err
+	}
+
+	overlapping, err := findOverlapping(tx, sub)
+	if err != nil {
+		return err
+	}
+
+	if len(overlapping) == 0 {
+		return insertAudited(tx, sub, meta)
+	}
+
+	switch policy {
+	case model.OverlapAllow:
+		if err := insertAudited(tx, sub, meta); err != nil {
+			return err
+		}
+		for _, existing := range overlapping {
+			sub.Warnings = append(sub.Warnings, fmt.Sprintf("overlaps with subscription %d", existing.ID))
+		}
+		return nil
+	case model.OverlapMerge:
+		if len(overlapping) == 1 && sameTerms(overlapping[0], *sub) {
+			return mergeInto(tx, &overlapping[0], sub, meta)
+		}
+		return fmt.Errorf("subscription overlaps with %d subscriptions that cannot be merged: %w", len(overlapping), model.ErrConflict)
+	default:
+		return fmt.Errorf("subscription overlaps with subscription %d: %w", overlapping[0].ID, model.ErrConflict)
+	}
+}
+
+// lockSubscriptionPair сериализует до конца транзакции создания и изменения подписок пользователя
+// userID на сервис serviceID. Блокировка берется до блокировки строк подписок, чтобы порядок
+// блокировок при создании и изменении совпадал
+func lockSubscriptionPair(tx *sql.Tx, userID uuid.UUID, serviceID uint32) error {
+	_, err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext($1::text || '/' || $2::text))", userID, serviceID)
+	if err != nil {
+		return fmt.Errorf("failed to lock subscriptions: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// findOverlapping блокирует и возвращает другие подписки того же пользователя на тот же сервис,
+// период которых пересекается с периодом sub. Сама sub (по ID) в результат не попадает
+func findOverlapping(tx *sql.Tx, sub *model.Subscription) ([]model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions 
+	          WHERE user_id = $1 AND service_id = $2 AND deleted_at IS NULL AND id <> $5 
+	            AND start_date <= COALESCE($3::date, 'infinity'::date) 
+	            AND COALESCE(end_date, 'infinity'::date) >= $4 
+	          ORDER BY start_date, id 
+	          FOR UPDATE`
+
+	rows, err := tx.Query(query, sub.UserID, sub.ServiceID, sub.EndDate, sub.StartDate, sub.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find overlapping subscriptions: %w", mapError(err))
+	}
+
+	return scanSubscriptions(rows)
+}
+
+// applyUpdatePolicy проверяет пересечение измененной подписки sub с другими подписками
+// и применяет политику policy: reject - ошибка, allow - предупреждение в sub.Warnings,
+// merge - единственная пересекающаяся подписка с теми же условиями поглощается sub.
+// Вызывается после lockSubscriptionPair и до записи sub
+func applyUpdatePolicy(tx *sql.Tx, sub *model.Subscription, policy model.OverlapPolicy, meta model.AuditMeta) error {
+	overlapping, err := findOverlapping(tx, sub)
+	if err != nil {
+		return err
+	}
+
+	if len(overlapping) == 0 {
+		return nil
+	}
+
+	switch policy {
+	case model.OverlapAllow:
+		for _, existing := range overlapping {
+			sub.Warnings = append(sub.Warnings, fmt.Sprintf("overlaps with subscription %d", existing.ID))
+		}
+		return nil
+	case model.OverlapMerge:
+		if len(overlapping) == 1 && sameTerms(overlapping[0], *sub) {
+			return absorb(tx, sub, &overlapping[0], meta)
+		}
+		return fmt.Errorf("subscription overlaps with %d subscriptions that cannot be merged: %w", len(overlapping), model.ErrConflict)
+	default:
+		return fmt.Errorf("subscription overlaps with subscription %d: %w", overlapping[0].ID, model.ErrConflict)
+	}
+}
+
+// absorb расширяет период изменяемой подписки sub так, чтобы он покрывал период other,
+// и помечает other удаленной с записью в журнал
+func absorb(tx *sql.Tx, sub *model.Subscription, other *model.Subscription, meta model.AuditMeta) error {
+	if other.StartDate.Before(sub.StartDate) {
+		sub.StartDate = other.StartDate
+	}
+	if sub.EndDate != nil && (other.EndDate == nil || other.EndDate.After(*sub.EndDate)) {
+		sub.EndDate = other.EndDate
+	}
+
+	query := `UPDATE subscriptions 
+	          SET deleted_at = now(), version = version + 1, updated_at = now() 
+	          WHERE id = $1 
+	          RETURNING ` + subscriptionColumns
+
+	after, err := scanSubscription(tx.QueryRow(query, other.ID))
+	if err != nil {
+		return fmt.Errorf("failed to merge subscription: %w", mapError(err))
+	}
+
+	if err := writeAudit(tx, model.AuditMerge, other.ID, other, after, meta); err != nil {
+		return err
+	}
+
+	log.Printf("Merged subscription %d into updated subscription %d", other.ID, sub.ID)
+
+	sub.Warnings = append(sub.Warnings, fmt.Sprintf("merged subscription %d into this subscription", other.ID))
+	return nil
+}
+
+// insertAudited добавляет подписку и фиксирует создание в журнале изменений
+func insertAudited(tx *sql.Tx, sub *model.Subscription, meta model.AuditMeta) error {
+	if err := insertSubscription(tx, sub); err != nil {
+		return err
+	}
+
+	return writeAudit(tx, model.AuditCreate, sub.ID, nil, sub, meta)
+}
+
+// mergeInto расширяет период существующей подписки так, чтобы он покрывал период новой,
+// и возвращает в sub итоговое состояние существующей подписки
+func mergeInto(tx *sql.Tx, existing *model.Subscription, sub *model.Subscription, meta model.AuditMeta) error {
+	before := *existing
+
+	if sub.StartDate.Before(existing.StartDate) {
+		existing.StartDate = sub.StartDate
+	}
+	if existing.EndDate != nil && (sub.EndDate == nil || sub.EndDate.After(*existing.EndDate)) {
+		existing.EndDate = sub.EndDate
+	}
+
+	query := `UPDATE subscriptions 
+	          SET start_date = $1, end_date = $2, version = version + 1, updated_at = now() 
+	          WHERE id = $3 
+	          RETURNING version, updated_at`
+
+	err := tx.QueryRow(query, existing.StartDate, existing.EndDate, existing.ID).Scan(&existing.Version, &existing.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to merge subscription: %w", mapError(err))
+	}
+
+	if err := writeAudit(tx, model.AuditMerge, existing.ID, &before, existing, meta); err != nil {
+		return err
+	}
+
+	log.Printf("Merged new subscription into existing subscription %d", existing.ID)
+
+	*sub = *existing
+	sub.Warnings = []string{fmt.Sprintf("merged into existing subscription %d", existing.ID)}
+	return nil
+}
+
+// sameTerms сообщает, что подписки оплачиваются одинаково и одну можно объединить с другой
+func sameTerms(a, b model.Subscription) bool {
+	return a.Price == b.Price && a.Currency == b.Currency && a.BillingPeriod == b.BillingPeriod &&
+		a.BillingInterval == b.BillingInterval && a.AnchorDay == b.AnchorDay && a.TrialDays == b.TrialDays
+}
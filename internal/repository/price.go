@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/lib/pq"
+)
+
+// ListPriceChanges возвращает изменения цены подписки по возрастанию месяца
+func (r *subscriptionRepository) ListPriceChanges(subscriptionID uint32) ([]model.PriceChange, error) {
+	query := `SELECT effective_from, price 
+	          FROM subscription_prices 
+	          WHERE subscription_id = $1 
+	          ORDER BY effective_from`
+
+	rows, err := r.db.Query(query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price changes: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	changes := []model.PriceChange{}
+	for rows.Next() {
+		var change model.PriceChange
+		if err := rows.Scan(&change.EffectiveFrom, &change.Price); err != nil {
+			return nil, fmt.Errorf("failed to scan price change: %w", mapError(err))
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating price changes: %w", mapError(err))
+	}
+
+	return changes, nil
+}
+
+// SchedulePriceChange сохраняет цену, действующую с месяца change.EffectiveFrom.
+// Изменение с тем же месяцем перезаписывается. Если expectedVersion задан, изменение
+// сохраняется только при совпадении версии подписки
+func (r *subscriptionRepository) SchedulePriceChange(id uint32, change model.PriceChange, expectedVersion *uint32, meta model.AuditMeta) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		return changePricing(tx, id, expectedVersion, model.AuditPrice, meta, func() error {
+			query := `INSERT INTO subscription_prices (subscription_id, effective_from, price) 
+			          VALUES ($1, $2, $3) 
+			          ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = now()`
+
+			if _, err := tx.Exec(query, id, change.EffectiveFrom, change.Price); err != nil {
+				return fmt.Errorf("failed to schedule price change: %w", mapError(err))
+			}
+
+			return nil
+		})
+	})
+}
+
+// CancelPriceChange удаляет изменение цены подписки с указанного месяца. Если expectedVersion задан,
+// изменение удаляется только при совпадении версии подписки
+func (r *subscriptionRepository) CancelPriceChange(id uint32, effectiveFrom time.Time, expectedVersion *uint32, meta model.AuditMeta) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		return changePricing(tx, id, expectedVersion, model.AuditPrice, meta, func() error {
+			result, err := tx.Exec("DELETE FROM subscription_prices WHERE subscription_id = $1 AND effective_from = $2", id, effectiveFrom)
+			if err != nil {
+				return fmt.Errorf("failed to cancel price change: %w", mapError(err))
+			}
+
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", mapError(err))
+			}
+
+			if rowsAffected == 0 {
+				return fmt.Errorf("price change from %s for subscription with ID %d %w", effectiveFrom.Format("01-2006"), id, model.ErrNotFound)
+			}
+
+			return nil
+		})
+	})
+}
+
+// changePricing блокирует подписку, выполняет change над ее ценами и увеличивает версию подписки.
+// Снимки до и после изменения вместе с ценами и акциями записываются в журнал
+func changePricing(tx *sql.Tx, id uint32, expectedVersion *uint32, action model.AuditAction, meta model.AuditMeta, change func() error) error {
+	before, err := lockSubscription(tx, id, expectedVersion)
+	if err != nil {
+		return err
+	}
+	if err := attachPricing(tx, before); err != nil {
+		return err
+	}
+
+	if err := change(); err != nil {
+		return err
+	}
+
+	query := `UPDATE subscriptions 
+	          SET version = version + 1, updated_at = now() 
+	          WHERE id = $1 
+	          RETURNING ` + subscriptionColumns
+
+	after, err := scanSubscription(tx.QueryRow(query, id))
+	if err != nil {
+		return fmt.Errorf("failed to update subscription version: %w", mapError(err))
+	}
+	if err := attachPricing(tx, after); err != nil {
+		return err
+	}
+
+	return writeAudit(tx, action, id, before, after, meta)
+}
+
+// attachPricing заполняет изменения цены и акции одной подписки
+func attachPricing(q querier, sub *model.Subscription) error {
+	subscriptions := []model.Subscription{*sub}
+	if err := attachPriceChanges(q, subscriptions); err != nil {
+		return err
+	}
+	if err := attachPromotions(q, subscriptions); err != nil {
+		return err
+	}
+
+	*sub = subscriptions[0]
+	return nil
+}
+
+// attachPriceChanges заполняет PriceChanges у подписок одним запросом
+func attachPriceChanges(q querier, subscriptions []model.Subscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(subscriptions))
+	positions := make(map[uint32]int, len(subscriptions))
+	for i, sub := range subscriptions {
+		ids[i] = int64(sub.ID)
+		positions[sub.ID] = i
+	}
+
+	query := `SELECT subscription_id, effective_from, price 
+	          FROM subscription_prices 
+	          WHERE subscription_id = ANY($1) 
+	          ORDER BY subscription_id, effective_from`
+
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load price changes: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint32
+		var change model.PriceChange
+		if err := rows.Scan(&id, &change.EffectiveFrom, &change.Price); err != nil {
+			return fmt.Errorf("failed to scan price change: %w", mapError(err))
+		}
+
+		sub := &subscriptions[positions[id]]
+		sub.PriceChanges = append(sub.PriceChanges, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating price changes: %w", mapError(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/lib/pq"
+)
+
+// ListPromotions возвращает промо-цены подписки по возрастанию даты начала
+func (r *subscriptionRepository) ListPromotions(subscriptionID uint32) ([]model.Promotion, error) {
+	query := `SELECT id, start_date, end_date, price 
+	          FROM subscription_promotions 
+	          WHERE subscription_id = $1 
+	          ORDER BY start_date`
+
+	rows, err := r.db.Query(query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list promotions: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	promotions := []model.Promotion{}
+	for rows.Next() {
+		var promo model.Promotion
+		if err := rows.Scan(&promo.ID, &promo.StartDate, &promo.EndDate, &promo.Price); err != nil {
+			return nil, fmt.Errorf("failed to scan promotion: %w", mapError(err))
+		}
+		promotions = append(promotions, promo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating promotions: %w", mapError(err))
+	}
+
+	return promotions, nil
+}
+
+// AddPromotion сохраняет промо-цену подписки и заполняет ее ID. Период акции не должен пересекаться
+// с другими акциями подписки. Если expectedVersion задан, акция сохраняется только при совпадении версии подписки
+func (r *subscriptionRepository) AddPromotion(id uint32, promo *model.Promotion, expectedVersion *uint32, meta model.AuditMeta) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		return changePricing(tx, id, expectedVersion, model.AuditPromotion, meta, func() error {
+			var overlapping uint32
+			err := tx.QueryRow(`SELECT id FROM subscription_promotions 
+			                    WHERE subscription_id = $1 AND start_date <= $2 AND end_date >= $3 
+			                    LIMIT 1`, id, promo.EndDate, promo.StartDate).Scan(&overlapping)
+			switch {
+			case err == nil:
+				return fmt.Errorf("promotion overlaps with promotion %d: %w", overlapping, model.ErrConflict)
+			case err != sql.ErrNoRows:
+				return fmt.Errorf("failed to find overlapping promotions: %w", mapError(err))
+			}
+
+			query := `INSERT INTO subscription_promotions (subscription_id, start_date, end_date, price) 
+			          VALUES ($1, $2, $3, $4) RETURNING id`
+
+			if err := tx.QueryRow(query, id, promo.StartDate, promo.EndDate, promo.Price).Scan(&promo.ID); err != nil {
+				return fmt.Errorf("failed to add promotion: %w", mapError(err))
+			}
+
+			return nil
+		})
+	})
+}
+
+// DeletePromotion удаляет промо-цену подписки. Если expectedVersion задан, акция удаляется
+// только при совпадении версии подписки
+func (r *subscriptionRepository) DeletePromotion(id uint32, promotionID uint32, expectedVersion *uint32, meta model.AuditMeta) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		return changePricing(tx, id, expectedVersion, model.AuditPromotion, meta, func() error {
+			result, err := tx.Exec("DELETE FROM subscription_promotions WHERE subscription_id = $1 AND id = $2", id, promotionID)
+			if err != nil {
+				return fmt.Errorf("failed to delete promotion: %w", mapError(err))
+			}
+
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", mapError(err))
+			}
+
+			if rowsAffected == 0 {
+				return fmt.Errorf("promotion %d for subscription with ID %d %w", promotionID, id, model.ErrNotFound)
+			}
+
+			return nil
+		})
+	})
+}
+
+// attachPromotions заполняет Promotions у подписок одним запросом
+func attachPromotions(q querier, subscriptions []model.Subscription) error {
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(subscriptions))
+	positions := make(map[uint32]int, len(subscriptions))
+	for i, sub := range subscriptions {
+		ids[i] = int64(sub.ID)
+		positions[sub.ID] = i
+	}
+
+	query := `SELECT subscription_id, id, start_date, end_date, price 
+	          FROM subscription_promotions 
+	          WHERE subscription_id = ANY($1) 
+	          ORDER BY subscription_id, start_date`
+
+	rows, err := q.Query(query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to load promotions: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uint32
+		var promo model.Promotion
+		if err := rows.Scan(&id, &promo.ID, &promo.StartDate, &promo.EndDate, &promo.Price); err != nil {
+			return fmt.Errorf("failed to scan promotion: %w", mapError(err))
+		}
+
+		sub := &subscriptions[positions[id]]
+		sub.Promotions = append(sub.Promotions, promo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating promotions: %w", mapError(err))
+	}
+
+	return nil
+}
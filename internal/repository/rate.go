@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/lib/pq"
+)
+
+type rateRepository struct {
+	db *sql.DB
+}
+
+func NewRateRepository(db *sql.DB) *rateRepository {
+	return &rateRepository{db: db}
+}
+
+// Upsert сохраняет курсы в одной транзакции, заменяя уже загруженные курсы тех же месяцев
+func (r *rateRepository) Upsert(rates []model.ExchangeRate) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", mapError(err))
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO exchange_rates (currency, month, rate) 
+	          VALUES ($1, $2, $3) 
+	          ON CONFLICT (currency, month) DO UPDATE SET rate = EXCLUDED.rate, updated_at = now() 
+	          RETURNING updated_at`
+
+	for i := range rates {
+		err := tx.QueryRow(query, rates[i].Currency, rates[i].Month, rates[i].Rate).Scan(&rates[i].UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to store exchange rate: %w", mapError(err))
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// List возвращает курсы, упорядоченные по валюте и месяцу
+func (r *rateRepository) List(currency *string) ([]model.ExchangeRate, error) {
+	query := `SELECT currency, month, rate, updated_at 
+	          FROM exchange_rates 
+	          WHERE $1::text IS NULL OR currency = $1 
+	          ORDER BY currency, month`
+
+	rows, err := r.db.Query(query, currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exchange rates: %w", mapError(err))
+	}
+
+	return scanRates(rows)
+}
+
+// ListUpTo возвращает курсы указанных валют, действующие с месяцев не позже to
+func (r *rateRepository) ListUpTo(currencies []string, to time.Time) ([]model.ExchangeRate, error) {
+	query := `SELECT currency, month, rate, updated_at 
+	          FROM exchange_rates 
+	          WHERE currency = ANY($1) AND month <= $2 
+	          ORDER BY currency, month`
+
+	rows, err := r.db.Query(query, pq.Array(currencies), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exchange rates: %w", mapError(err))
+	}
+
+	return scanRates(rows)
+}
+
+// scanRates читает все курсы выборки и закрывает ее
+func scanRates(rows *sql.Rows) ([]model.ExchangeRate, error) {
+	defer rows.Close()
+
+	rates := []model.ExchangeRate{}
+	for rows.Next() {
+		var rate model.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Month, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan exchange rate: %w", mapError(err))
+		}
+		rates = append(rates, rate)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating exchange rates: %w", mapError(err))
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+type RateRepository interface {
+	Upsert(rates []model.ExchangeRate) error
+	List(currency *string) ([]model.ExchangeRate, error)
+	ListUpTo(currencies []string, to time.Time) ([]model.ExchangeRate, error)
+}
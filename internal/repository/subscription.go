@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/Fedasov/Effective-Mobile/internal/model"
@@ -19,120 +20,536 @@ func NewSubscriptionRepository(db *sql.DB) *subscriptionRepository {
 	return &subscriptionRepository{db: db}
 }
 
-func (r *subscriptionRepository) Create(sub *model.Subscription) error {
-	query := `INSERT INTO subscriptions (service_name, price, user_id, start_date, end_date) 
-	          VALUES ($1, $2, $3, $4, $5) RETURNING id`
-	return r.db.QueryRow(query, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate).Scan(&sub.ID)
-}
+// subscriptionColumns - список колонок, который читают все выборки подписок
+const subscriptionColumns = "id, service_id, service_name, price, currency, billing_period, billing_interval, anchor_day, trial_days, user_id, start_date, end_date, version, updated_at, deleted_at"
 
-func (r *subscriptionRepository) GetByID(id uint32) (*model.Subscription, error) {
-	query := `SELECT id, service_name, price, user_id, start_date, end_date 
-	          FROM subscriptions WHERE id = $1`
+// trialEndColumn - последний день пробного периода, как при расчете списаний:
+// trial_days-й день с даты начала, но не позже даты окончания (LEAST пропускает NULL)
+const trialEndColumn = "LEAST(start_date + trial_days - 1, end_date)"
 
-	row := r.db.QueryRow(query, id)
+// queryRower обобщает *sql.DB и *sql.Tx для запросов, которые выполняются как в транзакции, так и вне ее
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
 
-	var sub model.Subscription
-	var endDate sql.NullTime
+// querier обобщает *sql.DB и *sql.Tx для выборок из нескольких строк
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
 
-	err := row.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &endDate)
+// inTx выполняет fn в транзакции и фиксирует ее, если fn завершилась без ошибки
+func (r *subscriptionRepository) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := r.db.Begin()
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("subscription with ID %d not found", id)
-		}
-		return nil, fmt.Errorf("failed to get subscription: %v", err)
+		return fmt.Errorf("failed to begin transaction: %w", mapError(err))
 	}
+	defer tx.Rollback()
 
-	if endDate.Valid {
-		sub.EndDate = &endDate.Time
+	if err := fn(tx); err != nil {
+		return err
 	}
 
-	return &sub, nil
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", mapError(err))
+	}
+
+	return nil
 }
 
-func (r *subscriptionRepository) Update(sub *model.Subscription) error {
-	query := `UPDATE subscriptions 
-	          SET service_name = $1, price = $2, user_id = $3, start_date = $4, end_date = $5 
-	          WHERE id = $6`
+func (r *subscriptionRepository) Create(sub *model.Subscription, policy model.OverlapPolicy, meta model.AuditMeta) error {
+	return r.inTx(func(tx *sql.Tx) error {
+		return createWithPolicy(tx, sub, policy, meta)
+	})
+}
 
-	result, err := r.db.Exec(query, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate, sub.ID)
+// insertSubscription добавляет подписку и заполняет поля, которые назначает база данных
+func insertSubscription(q queryRower, sub *model.Subscription) error {
+	query := `INSERT INTO subscriptions (service_id, service_name, price, currency, billing_period, billing_interval, anchor_day, trial_days, 
+	              user_id, start_date, end_date) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id, version, updated_at`
+	err := q.QueryRow(query, sub.ServiceID, sub.ServiceName, sub.Price, sub.Currency, sub.BillingPeriod, sub.BillingInterval, sub.AnchorDay,
+		sub.TrialDays, sub.UserID, sub.StartDate, sub.EndDate).
+		Scan(&sub.ID, &sub.Version, &sub.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to update subscription: %v", err)
+		return fmt.Errorf("failed to insert subscription: %w", mapError(err))
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	return nil
+}
+
+// GetByID возвращает подписку по ID. Удаленные подписки возвращаются только при includeDeleted
+func (r *subscriptionRepository) GetByID(id uint32, includeDeleted bool) (*model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions WHERE id = $1 AND ($2 OR deleted_at IS NULL)`
+
+	sub, err := scanSubscription(r.db.QueryRow(query, id, includeDeleted))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("subscription with ID %d %w", id, model.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", mapError(err))
+	}
+
+	return sub, nil
+}
+
+// lockSubscription читает неудаленную подписку с блокировкой строки до конца транзакции
+// и проверяет ожидаемую клиентом версию, если она задана
+func lockSubscription(tx *sql.Tx, id uint32, expectedVersion *uint32) (*model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions WHERE id = $1 AND deleted_at IS NULL 
+	          FOR UPDATE`
+
+	sub, err := scanSubscription(tx.QueryRow(query, id))
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %v", err)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("subscription with ID %d %w", id, model.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to lock subscription: %w", mapError(err))
+	}
+
+	if expectedVersion != nil && *expectedVersion != sub.Version {
+		return nil, fmt.Errorf("subscription with ID %d was modified: %w", id, model.ErrPreconditionFailed)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("subscription with ID %d not found", sub.ID)
+	return sub, nil
+}
+
+// Update перезаписывает подписку. Если expectedVersion задан, запись изменяется только
+// при совпадении версии; строка блокируется на время проверки и изменения.
+// Пересечение с другими подписками обрабатывается по политике policy
+func (r *subscriptionRepository) Update(sub *model.Subscription, expectedVersion *uint32, policy model.OverlapPolicy, meta model.AuditMeta) error {
+	err := r.inTx(func(tx *sql.Tx) error {
+		return updateSubscription(tx, sub, expectedVersion, policy, meta)
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Printf("Updated subscription with ID: %d", sub.ID)
 	return nil
 }
 
-func (r *subscriptionRepository) Delete(id uint32) error {
-	query := "DELETE FROM subscriptions WHERE id = $1"
+// updateSubscription перезаписывает подписку в транзакции tx с проверкой пересечения периодов
+// и фиксирует изменение в журнале
+func updateSubscription(tx *sql.Tx, sub *model.Subscription, expectedVersion *uint32, policy model.OverlapPolicy, meta model.AuditMeta) error {
+	if err := resolveService(tx, sub); err != nil {
+		return err
+	}
+
+	if err := lockSubscriptionPair(tx, sub.UserID, sub.ServiceID); err != nil {
+		return err
+	}
+
+	before, err := lockSubscription(tx, sub.ID, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	if err := applyUpdatePolicy(tx, sub, policy, meta); err != nil {
+		return err
+	}
 
-	result, err := r.db.Exec(query, id)
+	query := `UPDATE subscriptions 
+	          SET service_id = $1, service_name = $2, price = $3, currency = $4, billing_period = $5, billing_interval = $6, 
+	              anchor_day = $7, trial_days = $8, user_id = $9, start_date = $10, end_date = $11, version = version + 1, updated_at = now() 
+	          WHERE id = $12 
+	          RETURNING version, updated_at`
+
+	err = tx.QueryRow(query, sub.ServiceID, sub.ServiceName, sub.Price, sub.Currency, sub.BillingPeriod, sub.BillingInterval, sub.AnchorDay,
+		sub.TrialDays, sub.UserID, sub.StartDate, sub.EndDate, sub.ID).
+		Scan(&sub.Version, &sub.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to delete subscription: %v", err)
+		return fmt.Errorf("failed to update subscription: %w", mapError(err))
+	}
+
+	return writeAudit(tx, model.AuditUpdate, sub.ID, before, sub, meta)
+}
+
+// Patch записывает только переданные колонки и возвращает обновленную подписку.
+// Пересечение с другими подписками обрабатывается по политике policy
+func (r *subscriptionRepository) Patch(id uint32, patch model.SubscriptionPatch, expectedVersion *uint32, policy model.OverlapPolicy, meta model.AuditMeta) (*model.Subscription, error) {
+	if patch.IsEmpty() {
+		return nil, fmt.Errorf("nothing to patch in subscription %d", id)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	var updated *model.Subscription
+	var changed int
+	err := r.inTx(func(tx *sql.Tx) error {
+		var service *model.Subscription
+		if patch.ServiceID != nil || patch.ServiceName != nil {
+			service = &model.Subscription{}
+			if patch.ServiceID != nil {
+				service.ServiceID = *patch.ServiceID
+			} else {
+				service.ServiceName = *patch.ServiceName
+			}
+			if err := resolveService(tx, service); err != nil {
+				return err
+			}
+		}
+
+		// Пару пользователь/сервис нужно заблокировать до строки подписки, поэтому
+		// итоговая пара сначала определяется по незаблокированной строке и сверяется после блокировки
+		current, err := scanSubscription(tx.QueryRow(`SELECT `+subscriptionColumns+` FROM subscriptions WHERE id = $1 AND deleted_at IS NULL`, id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("subscription with ID %d %w", id, model.ErrNotFound)
+			}
+			return fmt.Errorf("failed to get subscription: %w", mapError(err))
+		}
+
+		locked := patchedSubscription(*current, patch, service)
+		if err := lockSubscriptionPair(tx, locked.UserID, locked.ServiceID); err != nil {
+			return err
+		}
+
+		before, err := lockSubscription(tx, id, expectedVersion)
+		if err != nil {
+			return err
+		}
+
+		target := patchedSubscription(*before, patch, service)
+		if target.UserID != locked.UserID || target.ServiceID != locked.ServiceID {
+			return fmt.Errorf("subscription with ID %d was modified concurrently: %w", id, model.ErrConflict)
+		}
+
+		if err := applyUpdatePolicy(tx, &target, policy, meta); err != nil {
+			return err
+		}
+		if !target.StartDate.Equal(before.StartDate) {
+			patch.StartDate = &target.StartDate
+		}
+		if !sameEndDate(target.EndDate, before.EndDate) {
+			patch.EndDateSet = true
+			patch.EndDate = target.EndDate
+		}
+
+		var assignments []string
+		var args []interface{}
+
+		set := func(column string, value interface{}) {
+			args = append(args, value)
+			assignments = append(assignments, fmt.Sprintf("%s = $%d", column, len(args)))
+		}
+
+		if service != nil {
+			set("service_id", service.ServiceID)
+			set("service_name", service.ServiceName)
+		}
+		if patch.Price != nil {
+			set("price", *patch.Price)
+		}
+		if patch.Currency != nil {
+			set("currency", *patch.Currency)
+		}
+		if patch.BillingPeriod != nil {
+			set("billing_period", *patch.BillingPeriod)
+			set("billing_interval", *patch.BillingInterval)
+			set("anchor_day", *patch.AnchorDay)
+		}
+		if patch.TrialDays != nil {
+			set("trial_days", *patch.TrialDays)
+		}
+		if patch.UserID != nil {
+			set("user_id", *patch.UserID)
+		}
+		if patch.StartDate != nil {
+			set("start_date", *patch.StartDate)
+		}
+		if patch.EndDateSet {
+			set("end_date", patch.EndDate)
+		}
+
+		changed = len(assignments)
+		assignments = append(assignments, "version = version + 1", "updated_at = now()")
+		args = append(args, id)
+		query := fmt.Sprintf("UPDATE subscriptions SET %s WHERE id = $%d RETURNING %s",
+			strings.Join(assignments, ", "), len(args), subscriptionColumns)
+
+		updated, err = scanSubscription(tx.QueryRow(query, args...))
+		if err != nil {
+			return fmt.Errorf("failed to patch subscription: %w", mapError(err))
+		}
+		updated.Warnings = target.Warnings
+
+		return writeAudit(tx, model.AuditPatch, id, before, updated, meta)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %v", err)
+		return nil, err
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("subscription with ID %d not found", id)
+	log.Printf("Patched subscription with ID: %d (%d columns)", id, changed)
+	return updated, nil
+}
+
+// patchedSubscription возвращает подписку sub с примененными изменениями patch.
+// service - сервис из patch с уже определенными ID и названием или nil, если сервис не меняется
+func patchedSubscription(sub model.Subscription, patch model.SubscriptionPatch, service *model.Subscription) model.Subscription {
+	if service != nil {
+		sub.ServiceID = service.ServiceID
+		sub.ServiceName = service.ServiceName
+	}
+	if patch.Price != nil {
+		sub.Price = *patch.Price
+	}
+	if patch.Currency != nil {
+		sub.Currency = *patch.Currency
+	}
+	if patch.BillingPeriod != nil {
+		sub.BillingPeriod = *patch.BillingPeriod
+		sub.BillingInterval = *patch.BillingInterval
+		sub.AnchorDay = *patch.AnchorDay
+	}
+	if patch.TrialDays != nil {
+		sub.TrialDays = *patch.TrialDays
+	}
+	if patch.UserID != nil {
+		sub.UserID = *patch.UserID
+	}
+	if patch.StartDate != nil {
+		sub.StartDate = *patch.StartDate
+	}
+	if patch.EndDateSet {
+		sub.EndDate = patch.EndDate
+	}
+
+	return sub
+}
+
+// sameEndDate сравнивает даты окончания, где nil означает бессрочную подписку
+func sameEndDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(*b)
+}
+
+// Delete помечает подписку удаленной. Строка остается в таблице до очистки PurgeDeleted
+func (r *subscriptionRepository) Delete(id uint32, expectedVersion *uint32, meta model.AuditMeta) error {
+	err := r.inTx(func(tx *sql.Tx) error {
+		return deleteSubscription(tx, id, expectedVersion, meta)
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Printf("Deleted subscription with ID: %d", id)
 	return nil
 }
 
-func (r *subscriptionRepository) List(limit, offset int32) ([]model.Subscription, error) {
-	query := `SELECT id, service_name, price, user_id, start_date, end_date 
-	          FROM subscriptions 
-	          ORDER BY id 
-	          LIMIT $1 OFFSET $2`
+// deleteSubscription помечает подписку удаленной в транзакции tx и фиксирует изменение в журнале
+func deleteSubscription(tx *sql.Tx, id uint32, expectedVersion *uint32, meta model.AuditMeta) error {
+	before, err := lockSubscription(tx, id, expectedVersion)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE subscriptions 
+	          SET deleted_at = now(), version = version + 1, updated_at = now() 
+	          WHERE id = $1 
+	          RETURNING ` + subscriptionColumns
 
-	rows, err := r.db.Query(query, limit, offset)
+	after, err := scanSubscription(tx.QueryRow(query, id))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get subscriptions list: %v", err)
+		return fmt.Errorf("failed to delete subscription: %w", mapError(err))
 	}
-	defer rows.Close()
 
-	var subscriptions []model.Subscription
+	return writeAudit(tx, model.AuditDelete, id, before, after, meta)
+}
 
-	for rows.Next() {
-		var sub model.Subscription
-		var endDate sql.NullTime
+// Restore снимает отметку об удалении с подписки.
+// Пересечение с подписками, созданными после удаления, обрабатывается по политике policy, как при изменении
+func (r *subscriptionRepository) Restore(id uint32, policy model.OverlapPolicy, meta model.AuditMeta) (*model.Subscription, error) {
+	var restored *model.Subscription
+	err := r.inTx(func(tx *sql.Tx) error {
+		query := `SELECT ` + subscriptionColumns + ` 
+		          FROM subscriptions WHERE id = $1`
+
+		// Пара пользователь/сервис блокируется до строки подписки, как при изменении
+		current, err := scanSubscription(tx.QueryRow(query, id))
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("subscription with ID %d %w", id, model.ErrNotFound)
+			}
+			return fmt.Errorf("failed to get subscription: %w", mapError(err))
+		}
+
+		if err := lockSubscriptionPair(tx, current.UserID, current.ServiceID); err != nil {
+			return err
+		}
 
-		err := rows.Scan(&sub.ID, &sub.ServiceName, &sub.Price, &sub.UserID, &sub.StartDate, &endDate)
+		before, err := scanSubscription(tx.QueryRow(query+" FOR UPDATE", id))
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan subscription: %v", err)
+			if err == sql.ErrNoRows {
+				return fmt.Errorf("subscription with ID %d %w", id, model.ErrNotFound)
+			}
+			return fmt.Errorf("failed to lock subscription: %w", mapError(err))
+		}
+
+		if before.DeletedAt == nil {
+			return fmt.Errorf("subscription with ID %d is not deleted: %w", id, model.ErrConflict)
+		}
+		if before.UserID != current.UserID || before.ServiceID != current.ServiceID {
+			return fmt.Errorf("subscription with ID %d was modified concurrently: %w", id, model.ErrConflict)
+		}
+
+		target := *before
+		if err := applyUpdatePolicy(tx, &target, policy, meta); err != nil {
+			return err
 		}
 
-		if endDate.Valid {
-			sub.EndDate = &endDate.Time
+		query = `UPDATE subscriptions 
+		         SET deleted_at = NULL, start_date = $2, end_date = $3, version = version + 1, updated_at = now() 
+		         WHERE id = $1 
+		         RETURNING ` + subscriptionColumns
+
+		restored, err = scanSubscription(tx.QueryRow(query, id, target.StartDate, target.EndDate))
+		if err != nil {
+			return fmt.Errorf("failed to restore subscription: %w", mapError(err))
 		}
+		restored.Warnings = target.Warnings
+
+		return writeAudit(tx, model.AuditRestore, id, before, restored, meta)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Restored subscription with ID: %d", id)
+	return restored, nil
+}
 
-		subscriptions = append(subscriptions, sub)
+// PurgeDeleted окончательно удаляет подписки, помеченные удаленными раньше указанного момента.
+// Журнал изменений удаленных подписок сохраняется
+func (r *subscriptionRepository) PurgeDeleted(before time.Time) (int64, error) {
+	result, err := r.db.Exec("DELETE FROM subscriptions WHERE deleted_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted subscriptions: %w", mapError(err))
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating subscriptions: %v", err)
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", mapError(err))
+	}
+
+	return deleted, nil
+}
+
+func (r *subscriptionRepository) List(filter model.SubscriptionFilter) ([]model.Subscription, error) {
+	query, args := selectSubscriptions(filter)
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscriptions list: %w", mapError(err))
+	}
+
+	return scanSubscriptions(rows)
+}
+
+// Stream передает fn подписки по фильтру по мере чтения из базы, не загружая выборку в память.
+// Limit и Offset фильтра не применяются; ошибка fn прерывает чтение и возвращается без изменений
+func (r *subscriptionRepository) Stream(filter model.SubscriptionFilter, fn func(*model.Subscription) error) error {
+	query, args := selectSubscriptions(filter)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to stream subscriptions: %w", mapError(err))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return fmt.Errorf("failed to scan subscription: %w", mapError(err))
+		}
+
+		if err := fn(sub); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating subscriptions: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// selectSubscriptions строит запрос выборки по фильтру с условием курсора и сортировкой, без LIMIT и OFFSET
+func selectSubscriptions(filter model.SubscriptionFilter) (string, []interface{}) {
+	conditions, args := filterConditions(filter)
+
+	addArg := func(arg interface{}) string {
+		args = append(args, arg)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.After != nil {
+		conditions = append(conditions, keysetCondition(*filter.After, addArg))
+	}
+
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query += " ORDER BY " + orderByClause(filter.SortBy, filter.SortDesc)
+	return query, args
+}
+
+func (r *subscriptionRepository) Count(filter model.SubscriptionFilter) (int64, error) {
+	conditions, args := filterConditions(filter)
+
+	query := "SELECT COUNT(*) FROM subscriptions"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRow(query, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to count subscriptions: %w", mapError(err))
+	}
+
+	return total, nil
+}
+
+func (r *subscriptionRepository) ListByUser(userID uuid.UUID) ([]model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions 
+	          WHERE user_id = $1 AND deleted_at IS NULL 
+	          ORDER BY start_date, id`
+
+	rows, err := r.db.Query(query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user subscriptions: %w", mapError(err))
+	}
+
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := attachPriceChanges(r.db, subscriptions); err != nil {
+		return nil, err
+	}
+	if err := attachPromotions(r.db, subscriptions); err != nil {
+		return nil, err
 	}
 
 	return subscriptions, nil
 }
 
-func (r *subscriptionRepository) CalculateTotalCost(startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (int32, error) {
-	query := `SELECT COALESCE(SUM(price), 0) FROM subscriptions 
-	          WHERE start_date <= $1 AND (end_date IS NULL OR end_date >= $2)`
+// ListByPeriod возвращает подписки, действующие хотя бы в один день периода, вместе с изменениями цены
+func (r *subscriptionRepository) ListByPeriod(startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) ([]model.Subscription, error) {
+	query := `SELECT ` + subscriptionColumns + ` 
+	          FROM subscriptions 
+	          WHERE start_date <= $1 AND (end_date IS NULL OR end_date >= $2) AND deleted_at IS NULL`
 	args := []interface{}{endDate, startDate}
 
 	paramIndex := 3
@@ -142,15 +559,189 @@ func (r *subscriptionRepository) CalculateTotalCost(startDate, endDate time.Time
 		paramIndex++
 	}
 	if serviceName != nil {
-		query += fmt.Sprintf(" AND service_name = $%d", paramIndex)
-		args = append(args, *serviceName)
+		query += fmt.Sprintf(" AND service_id = (SELECT service_id FROM service_aliases WHERE alias_key = $%d)", paramIndex)
+		args = append(args, serviceKey(*serviceName))
 	}
+	query += " ORDER BY id"
 
-	var total int32
-	err := r.db.QueryRow(query, args...).Scan(&total)
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
-		return 0, fmt.Errorf("failed to calculate total cost: %v", err)
+		return nil, fmt.Errorf("failed to get subscriptions for period: %w", mapError(err))
 	}
 
-	return total, nil
+	subscriptions, err := scanSubscriptions(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := attachPriceChanges(r.db, subscriptions); err != nil {
+		return nil, err
+	}
+	if err := attachPromotions(r.db, subscriptions); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// rowScanner обобщает *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSubscription читает подписку из строки, выбранной с колонками subscriptionColumns
+func scanSubscription(row rowScanner) (*model.Subscription, error) {
+	var sub model.Subscription
+	var endDate, deletedAt sql.NullTime
+
+	err := row.Scan(&sub.ID, &sub.ServiceID, &sub.ServiceName, &sub.Price, &sub.Currency,
+		&sub.BillingPeriod, &sub.BillingInterval, &sub.AnchorDay, &sub.TrialDays, &sub.UserID, &sub.StartDate, &endDate, &sub.Version, &sub.UpdatedAt, &deletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if endDate.Valid {
+		sub.EndDate = &endDate.Time
+	}
+	if deletedAt.Valid {
+		sub.DeletedAt = &deletedAt.Time
+	}
+
+	return &sub, nil
+}
+
+// scanSubscriptions читает все строки выборки и закрывает ее
+func scanSubscriptions(rows *sql.Rows) ([]model.Subscription, error) {
+	defer rows.Close()
+
+	var subscriptions []model.Subscription
+
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", mapError(err))
+		}
+
+		subscriptions = append(subscriptions, *sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscriptions: %w", mapError(err))
+	}
+
+	return subscriptions, nil
+}
+
+// filterConditions строит условия WHERE и их аргументы по фильтру списка
+func filterConditions(filter model.SubscriptionFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if filter.UserID != nil {
+		addCondition("user_id = $%d", *filter.UserID)
+	}
+	if filter.ServiceName != nil {
+		addCondition("service_id = (SELECT service_id FROM service_aliases WHERE alias_key = $%d)", serviceKey(*filter.ServiceName))
+	}
+	if filter.ServiceNamePrefix != nil {
+		addCondition("service_name ILIKE $%d", escapeLike(*filter.ServiceNamePrefix)+"%")
+	}
+	if filter.MinPrice != nil {
+		addCondition("price >= $%d", *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		addCondition("price <= $%d", *filter.MaxPrice)
+	}
+	if filter.ActiveAt != nil {
+		addCondition("start_date <= $%d", *filter.ActiveAt)
+		addCondition("(end_date IS NULL OR end_date >= $%d)", *filter.ActiveAt)
+	}
+	if filter.StartFrom != nil {
+		addCondition("start_date >= $%d", *filter.StartFrom)
+	}
+	if filter.StartTo != nil {
+		addCondition("start_date <= $%d", *filter.StartTo)
+	}
+	if filter.EndFrom != nil {
+		addCondition("end_date >= $%d", *filter.EndFrom)
+	}
+	if filter.EndTo != nil {
+		addCondition("end_date <= $%d", *filter.EndTo)
+	}
+	if filter.InTrial || filter.TrialEndsBy != nil {
+		conditions = append(conditions, "trial_days > 0")
+		addCondition("start_date <= $%d", filter.Today)
+		addCondition(trialEndColumn+" >= $%d", filter.Today)
+	}
+	if filter.TrialEndsBy != nil {
+		addCondition(trialEndColumn+" <= $%d", *filter.TrialEndsBy)
+	}
+
+	return conditions, args
+}
+
+// keysetCondition строит условие "строго после курсора" с учетом направления сортировки
+// и того, что NULL-значения идут в конце
+func keysetCondition(cursor model.SubscriptionCursor, addArg func(interface{}) string) string {
+	column := sortColumn(cursor.SortBy)
+
+	cmp := ">"
+	if cursor.SortDesc {
+		cmp = "<"
+	}
+
+	id := addArg(cursor.ID)
+	if column == "id" {
+		return fmt.Sprintf("id %s %s", cmp, id)
+	}
+
+	if cursor.Value == nil {
+		return fmt.Sprintf("(%s IS NULL AND id %s %s)", column, cmp, id)
+	}
+
+	value := addArg(*cursor.Value)
+	return fmt.Sprintf("(%s %s %s OR %s IS NULL OR (%s = %s AND id %s %s))",
+		column, cmp, value, column, column, value, cmp, id)
+}
+
+// sortColumn возвращает колонку сортировки, если она разрешена, иначе id
+func sortColumn(sortBy string) string {
+	for _, field := range model.SubscriptionSortFields {
+		if field == sortBy {
+			return field
+		}
+	}
+
+	return "id"
+}
+
+// orderByClause строит выражение сортировки только из разрешенных колонок,
+// добавляя id для стабильного порядка
+func orderByClause(sortBy string, desc bool) string {
+	column := sortColumn(sortBy)
+
+	direction := "ASC"
+	if desc {
+		direction = "DESC"
+	}
+
+	if column == "id" {
+		return "id " + direction
+	}
+
+	return fmt.Sprintf("%s %s NULLS LAST, id %s", column, direction, direction)
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+	return replacer.Replace(value)
 }
@@ -8,10 +8,28 @@ import (
 )
 
 type SubscriptionRepository interface {
-	Create(sub *model.Subscription) error
-	GetByID(id uint32) (*model.Subscription, error)
-	Update(sub *model.Subscription) error
-	Delete(id uint32) error
-	List(limit, offset int32) ([]model.Subscription, error)
-	CalculateTotalCost(startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) (int32, error)
+	Create(sub *model.Subscription, policy model.OverlapPolicy, meta model.AuditMeta) error
+	CreateIdempotent(sub *model.Subscription, key model.IdempotencyKey, policy model.OverlapPolicy, meta model.AuditMeta) (bool, error)
+	PurgeIdempotencyKeys(before time.Time) (int64, error)
+	CreateBatch(subs []*model.Subscription, policy model.OverlapPolicy, meta model.AuditMeta, atomic bool) ([]error, error)
+	UpdateBatch(subs []*model.Subscription, expectedVersions []*uint32, policy model.OverlapPolicy, meta model.AuditMeta, atomic bool) ([]error, error)
+	DeleteBatch(ids []uint32, meta model.AuditMeta, atomic bool) ([]error, error)
+	GetByID(id uint32, includeDeleted bool) (*model.Subscription, error)
+	Update(sub *model.Subscription, expectedVersion *uint32, policy model.OverlapPolicy, meta model.AuditMeta) error
+	Patch(id uint32, patch model.SubscriptionPatch, expectedVersion *uint32, policy model.OverlapPolicy, meta model.AuditMeta) (*model.Subscription, error)
+	Delete(id uint32, expectedVersion *uint32, meta model.AuditMeta) error
+	Restore(id uint32, policy model.OverlapPolicy, meta model.AuditMeta) (*model.Subscription, error)
+	PurgeDeleted(before time.Time) (int64, error)
+	ListAudit(subscriptionID uint32) ([]model.AuditEntry, error)
+	ListPriceChanges(subscriptionID uint32) ([]model.PriceChange, error)
+	SchedulePriceChange(id uint32, change model.PriceChange, expectedVersion *uint32, meta model.AuditMeta) error
+	CancelPriceChange(id uint32, effectiveFrom time.Time, expectedVersion *uint32, meta model.AuditMeta) error
+	ListPromotions(subscriptionID uint32) ([]model.Promotion, error)
+	AddPromotion(id uint32, promo *model.Promotion, expectedVersion *uint32, meta model.AuditMeta) error
+	DeletePromotion(id uint32, promotionID uint32, expectedVersion *uint32, meta model.AuditMeta) error
+	List(filter model.SubscriptionFilter) ([]model.Subscription, error)
+	Stream(filter model.SubscriptionFilter, fn func(*model.Subscription) error) error
+	Count(filter model.SubscriptionFilter) (int64, error)
+	ListByUser(userID uuid.UUID) ([]model.Subscription, error)
+	ListByPeriod(startDate, endDate time.Time, userID *uuid.UUID, serviceName *string) ([]model.Subscription, error)
 }
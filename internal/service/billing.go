@@ -0,0 +1,234 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// billingPeriod возвращает периодичность списаний из запроса или BillingMonthly
+func billingPeriod(req model.SubscriptionCreateRequest) string {
+	if req.BillingPeriod == nil {
+		return model.BillingMonthly
+	}
+
+	return *req.BillingPeriod
+}
+
+// requestBilling возвращает периодичность, длину периода и день списания из проверенного запроса.
+// День списания по умолчанию - день даты начала
+func requestBilling(req model.SubscriptionCreateRequest, startDate time.Time) (string, int32, int32) {
+	period := billingPeriod(req)
+
+	interval := model.BillingIntervals[period]
+	if req.BillingInterval != nil {
+		interval = *req.BillingInterval
+	}
+
+	var anchorDay int32
+	switch {
+	case req.AnchorDay != nil:
+		anchorDay = *req.AnchorDay
+	case period == model.BillingWeekly:
+		anchorDay = isoWeekday(startDate)
+	default:
+		anchorDay = int32(startDate.Day())
+	}
+
+	return period, interval, anchorDay
+}
+
+// isoWeekday возвращает день недели по ISO 8601: 1 - понедельник, 7 - воскресенье
+func isoWeekday(date time.Time) int32 {
+	if date.Weekday() == time.Sunday {
+		return 7
+	}
+
+	return int32(date.Weekday())
+}
+
+// charge - списание по подписке
+type charge struct {
+	date   time.Time
+	amount model.Amount
+}
+
+// billingCycle вычисляет даты списаний подписки. Первое списание приходится на дату начала,
+// следующие - на день списания в каждом периоде начиная с ближайшего дня списания после даты начала
+type billingCycle struct {
+	sub    model.Subscription
+	weekly bool
+	// Длина периода в месяцах или неделях
+	step int
+	// Смещение в месяцах или неделях от начала подписки до второго списания
+	offset int
+}
+
+func newBillingCycle(sub model.Subscription) billingCycle {
+	cycle := billingCycle{
+		sub:    sub,
+		weekly: sub.BillingPeriod == model.BillingWeekly,
+		step:   int(sub.BillingInterval),
+	}
+	if cycle.step < 1 {
+		cycle.step = 1
+	}
+	if !cycle.anchorAt(0).After(sub.StartDate) {
+		cycle.offset = cycle.step
+	}
+
+	return cycle
+}
+
+// anchorAt возвращает день списания в месяце или неделе, отстоящей на units от начала подписки.
+// В коротких месяцах списание переносится на последний день месяца
+func (c billingCycle) anchorAt(units int) time.Time {
+	start := c.sub.StartDate
+	if c.weekly {
+		monday := time.Date(start.Year(), start.Month(), start.Day()-int(isoWeekday(start))+1, 0, 0, 0, 0, time.UTC)
+		return monday.AddDate(0, 0, 7*units+int(c.sub.AnchorDay)-1)
+	}
+
+	month := time.Date(start.Year(), start.Month()+time.Month(units), 1, 0, 0, 0, 0, time.UTC)
+	last := lastDayOfMonth(month)
+	if int(c.sub.AnchorDay) > last.Day() {
+		return last
+	}
+
+	return month.AddDate(0, 0, int(c.sub.AnchorDay)-1)
+}
+
+// date возвращает дату списания с номером k, начиная с 0
+func (c billingCycle) date(k int) time.Time {
+	if k == 0 {
+		return time.Date(c.sub.StartDate.Year(), c.sub.StartDate.Month(), c.sub.StartDate.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	return c.anchorAt(c.offset + (k-1)*c.step)
+}
+
+// firstBefore возвращает номер списания, которое заведомо не позже from, чтобы не перебирать
+// списания с начала подписки
+func (c billingCycle) firstBefore(from time.Time) int {
+	start := c.sub.StartDate
+
+	var units int
+	if c.weekly {
+		units = int(from.Sub(start)/(24*time.Hour)) / 7
+	} else {
+		units = (from.Year()-start.Year())*12 + int(from.Month()) - int(start.Month())
+	}
+
+	if k := units/c.step - 1; k > 0 {
+		return k
+	}
+
+	return 0
+}
+
+// prorate возвращает сумму списания k за дни периода, в которые действовала подписка: каждый день
+// оплачивается по своей цене, а сумма делится на длину полного периода.
+// Первый период короче полного, если день списания не совпадает с днем даты начала
+func (c billingCycle) prorate(k int) (model.Amount, error) {
+	cycleStart := c.anchorAt(c.offset + (k-1)*c.step)
+	cycleEnd := c.anchorAt(c.offset + k*c.step)
+
+	usedEnd := cycleEnd
+	if c.sub.EndDate != nil && c.sub.EndDate.Before(usedEnd) {
+		usedEnd = c.sub.EndDate.AddDate(0, 0, 1)
+	}
+
+	charged := c.date(k)
+	var sum costSum
+	for day := charged; day.Before(usedEnd); day = day.AddDate(0, 0, 1) {
+		sum.add(chargePrice(c.sub, charged, day))
+	}
+
+	amount, err := sum.result()
+	if err != nil {
+		return 0, err
+	}
+
+	return amount.Prorate(1, daysBetween(cycleStart, cycleEnd))
+}
+
+func daysBetween(from, to time.Time) int64 {
+	return int64(to.Sub(from) / (24 * time.Hour))
+}
+
+// chargePrice возвращает цену периода подписки, действующую в день day, для списания от даты charged:
+// в пробном периоде подписка бесплатна, во время акции действует цена акции, иначе - цена на дату списания
+func chargePrice(sub model.Subscription, charged, day time.Time) model.Amount {
+	if inTrial(sub, day) {
+		return 0
+	}
+
+	for _, promo := range sub.Promotions {
+		if !day.Before(promo.StartDate) && !day.After(promo.EndDate) {
+			return promo.Price
+		}
+	}
+
+	return priceAt(sub, charged)
+}
+
+// inTrial сообщает, что день day попадает в пробный период подписки
+func inTrial(sub model.Subscription, day time.Time) bool {
+	end, ok := trialEnd(sub)
+	return ok && !day.Before(sub.StartDate) && !day.After(end)
+}
+
+// trialEnd возвращает последний день пробного периода: trial_days-й день с даты начала,
+// но не позже даты окончания подписки. Фильтры списка в репозитории считают его так же
+func trialEnd(sub model.Subscription) (time.Time, bool) {
+	if sub.TrialDays <= 0 {
+		return time.Time{}, false
+	}
+
+	end := sub.StartDate.AddDate(0, 0, int(sub.TrialDays)-1)
+	if sub.EndDate != nil && sub.EndDate.Before(end) {
+		end = *sub.EndDate
+	}
+
+	return end, true
+}
+
+// subscriptionCharges возвращает списания подписки с датами в периоде [from, to] по цене на дату списания
+// с учетом пробного периода и акций.
+// При prorate период, в котором подписка действовала не все дни, оплачивается пропорционально дням использования
+func subscriptionCharges(sub model.Subscription, from, to time.Time, prorate bool) ([]charge, error) {
+	cycle := newBillingCycle(sub)
+
+	var charges []charge
+	for k := cycle.firstBefore(from); ; k++ {
+		date := cycle.date(k)
+		if date.After(to) || (sub.EndDate != nil && date.After(*sub.EndDate)) {
+			break
+		}
+		if date.Before(from) {
+			continue
+		}
+
+		amount := chargePrice(sub, date, date)
+		if prorate {
+			var err error
+			if amount, err = cycle.prorate(k); err != nil {
+				return nil, err
+			}
+		}
+
+		charges = append(charges, charge{date: date, amount: amount})
+	}
+
+	return charges, nil
+}
+
+// monthlyEquivalent приводит цену периода списания к одному месяцу
+func monthlyEquivalent(sub model.Subscription, price model.Amount) (model.Amount, error) {
+	if sub.BillingPeriod == model.BillingWeekly {
+		// В году 52 недели и 12 месяцев
+		return price.Prorate(52, 12*int64(sub.BillingInterval))
+	}
+
+	return price.Prorate(1, int64(sub.BillingInterval))
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func datePtr(year int, month time.Month, day int) *time.Time {
+	d := utcDate(year, month, day)
+	return &d
+}
+
+func TestBillingCycleDate(t *testing.T) {
+	tests := []struct {
+		name string
+		sub  model.Subscription
+		want []time.Time
+	}{
+		{
+			name: "anchor 31 in February",
+			sub: model.Subscription{
+				BillingPeriod: model.BillingMonthly, BillingInterval: 1, AnchorDay: 31,
+				StartDate: utcDate(2025, time.January, 31),
+			},
+			want: []time.Time{
+				utcDate(2025, time.January, 31), utcDate(2025, time.February, 28),
+				utcDate(2025, time.March, 31), utcDate(2025, time.April, 30),
+			},
+		},
+		{
+			name: "anchor 31 in leap February",
+			sub: model.Subscription{
+				BillingPeriod: model.BillingMonthly, BillingInterval: 1, AnchorDay: 31,
+				StartDate: utcDate(2024, time.January, 15),
+			},
+			want: []time.Time{
+				utcDate(2024, time.January, 15), utcDate(2024, time.January, 31),
+				utcDate(2024, time.February, 29), utcDate(2024, time.March, 31),
+			},
+		},
+		{
+			name: "yearly from February 29",
+			sub: model.Subscription{
+				BillingPeriod: model.BillingYearly, BillingInterval: 12, AnchorDay: 29,
+				StartDate: utcDate(2024, time.February, 29),
+			},
+			want: []time.Time{
+				utcDate(2024, time.February, 29), utcDate(2025, time.February, 28),
+				utcDate(2026, time.February, 28), utcDate(2027, time.February, 28),
+				utcDate(2028, time.February, 29),
+			},
+		},
+		{
+			name: "quarterly with anchor before start day",
+			sub: model.Subscription{
+				BillingPeriod: model.BillingQuarterly, BillingInterval: 3, AnchorDay: 1,
+				StartDate: utcDate(2025, time.November, 20),
+			},
+			want: []time.Time{
+				utcDate(2025, time.November, 20), utcDate(2026, time.February, 1),
+				utcDate(2026, time.May, 1), utcDate(2026, time.August, 1),
+			},
+		},
+		{
+			name: "weekly on Monday",
+			sub: model.Subscription{
+				BillingPeriod: model.BillingWeekly, BillingInterval: 1, AnchorDay: 1,
+				StartDate: utcDate(2025, time.January, 15),
+			},
+			want: []time.Time{
+				utcDate(2025, time.January, 15), utcDate(2025, time.January, 20),
+				utcDate(2025, time.January, 27), utcDate(2025, time.February, 3),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cycle := newBillingCycle(tt.sub)
+			for k, want := range tt.want {
+				if got := cycle.date(k); !got.Equal(want) {
+					t.Errorf("date(%d) = %s, want %s", k, got.Format(time.DateOnly), want.Format(time.DateOnly))
+				}
+			}
+		})
+	}
+}
+
+func TestSubscriptionCharges(t *testing.T) {
+	quarterly := model.Subscription{
+		Price: 30000, BillingPeriod: model.BillingQuarterly, BillingInterval: 3, AnchorDay: 15,
+		StartDate: utcDate(2025, time.January, 15),
+	}
+	yearly := model.Subscription{
+		Price: 120000, BillingPeriod: model.BillingYearly, BillingInterval: 12, AnchorDay: 29,
+		StartDate: utcDate(2024, time.February, 29),
+	}
+	yearlyEnded := yearly
+	yearlyEnded.EndDate = datePtr(2026, time.February, 27)
+
+	tests := []struct {
+		name     string
+		sub      model.Subscription
+		from, to time.Time
+		want     []time.Time
+	}{
+		{
+			name: "quarterly window starts after charge",
+			sub:  quarterly,
+			from: utcDate(2025, time.April, 16), to: utcDate(2025, time.October, 15),
+			want: []time.Time{utcDate(2025, time.July, 15), utcDate(2025, time.October, 15)},
+		},
+		{
+			name: "quarterly window ends before charge",
+			sub:  quarterly,
+			from: utcDate(2025, time.April, 15), to: utcDate(2025, time.October, 14),
+			want: []time.Time{utcDate(2025, time.April, 15), utcDate(2025, time.July, 15)},
+		},
+		{
+			name: "quarterly window far from start",
+			sub:  quarterly,
+			from: utcDate(2027, time.February, 1), to: utcDate(2027, time.December, 31),
+			want: []time.Time{utcDate(2027, time.April, 15), utcDate(2027, time.July, 15), utcDate(2027, time.October, 15)},
+		},
+		{
+			name: "quarterly window between charges",
+			sub:  quarterly,
+			from: utcDate(2025, time.May, 1), to: utcDate(2025, time.June, 30),
+		},
+		{
+			name: "yearly window on the short February",
+			sub:  yearly,
+			from: utcDate(2025, time.February, 28), to: utcDate(2025, time.February, 28),
+			want: []time.Time{utcDate(2025, time.February, 28)},
+		},
+		{
+			name: "yearly window across leap year",
+			sub:  yearly,
+			from: utcDate(2025, time.March, 1), to: utcDate(2028, time.February, 29),
+			want: []time.Time{utcDate(2026, time.February, 28), utcDate(2027, time.February, 28), utcDate(2028, time.February, 29)},
+		},
+		{
+			name: "yearly ends before charge",
+			sub:  yearlyEnded,
+			from: utcDate(2024, time.January, 1), to: utcDate(2030, time.December, 31),
+			want: []time.Time{utcDate(2024, time.February, 29), utcDate(2025, time.February, 28)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charges, err := subscriptionCharges(tt.sub, tt.from, tt.to, false)
+			if err != nil {
+				t.Fatalf("subscriptionCharges unexpected error: %v", err)
+			}
+			if len(charges) != len(tt.want) {
+				t.Fatalf("subscriptionCharges returned %d charges, want %d: %v", len(charges), len(tt.want), charges)
+			}
+			for i, c := range charges {
+				if !c.date.Equal(tt.want[i]) {
+					t.Errorf("charge %d date = %s, want %s", i, c.date.Format(time.DateOnly), tt.want[i].Format(time.DateOnly))
+				}
+				if c.amount != tt.sub.Price {
+					t.Errorf("charge %d amount = %s, want %s", i, c.amount, tt.sub.Price)
+				}
+			}
+		})
+	}
+}
+
+func TestBillingCycleProrate(t *testing.T) {
+	// Списание 1-го числа, подписка с 10 января: первый период - 22 дня из 31
+	monthly := model.Subscription{
+		Price: 3100, BillingPeriod: model.BillingMonthly, BillingInterval: 1, AnchorDay: 1,
+		StartDate: utcDate(2025, time.January, 10),
+	}
+	ended := monthly
+	ended.EndDate = datePtr(2025, time.February, 14)
+	trial := monthly
+	trial.TrialDays = 5
+	promo := monthly
+	promo.Promotions = []model.Promotion{{
+		StartDate: utcDate(2025, time.February, 1), EndDate: utcDate(2025, time.February, 14), Price: 0,
+	}}
+
+	tests := []struct {
+		name string
+		sub  model.Subscription
+		k    int
+		want model.Amount
+	}{
+		{name: "short first period", sub: monthly, k: 0, want: 2200},
+		{name: "full period", sub: monthly, k: 1, want: 3100},
+		{name: "ended mid period", sub: ended, k: 1, want: 1550},
+		{name: "trial days are free", sub: trial, k: 0, want: 1700},
+		{name: "promotion days at promotion price", sub: promo, k: 1, want: 1550},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newBillingCycle(tt.sub).prorate(tt.k)
+			if err != nil {
+				t.Fatalf("prorate(%d) unexpected error: %v", tt.k, err)
+			}
+			if got != tt.want {
+				t.Errorf("prorate(%d) = %s, want %s", tt.k, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrialEnd(t *testing.T) {
+	tests := []struct {
+		name   string
+		sub    model.Subscription
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name: "no trial",
+			sub:  model.Subscription{StartDate: utcDate(2025, time.January, 10)},
+		},
+		{
+			name:   "one day",
+			sub:    model.Subscription{StartDate: utcDate(2025, time.January, 10), TrialDays: 1},
+			want:   utcDate(2025, time.January, 10),
+			wantOK: true,
+		},
+		{
+			name:   "two weeks",
+			sub:    model.Subscription{StartDate: utcDate(2025, time.January, 10), TrialDays: 14},
+			want:   utcDate(2025, time.January, 23),
+			wantOK: true,
+		},
+		{
+			name:   "across February",
+			sub:    model.Subscription{StartDate: utcDate(2025, time.January, 31), TrialDays: 29},
+			want:   utcDate(2025, time.February, 28),
+			wantOK: true,
+		},
+		{
+			name: "capped at end date",
+			sub: model.Subscription{
+				StartDate: utcDate(2025, time.January, 10), EndDate: datePtr(2025, time.January, 15), TrialDays: 14,
+			},
+			want:   utcDate(2025, time.January, 15),
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := trialEnd(tt.sub)
+			if ok != tt.wantOK {
+				t.Fatalf("trialEnd ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("trialEnd = %s, want %s", got.Format(time.DateOnly), tt.want.Format(time.DateOnly))
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// maxBulkItems ограничивает размер пакета, чтобы одна транзакция не держала блокировки слишком долго
+const maxBulkItems = 1000
+
+func (s *subscriptionService) BulkCreate(req model.BulkCreateRequest, meta model.AuditMeta) (*model.BulkResult, error) {
+	log.Printf("Creating %d subscriptions in bulk", len(req.Items))
+
+	mode, err := parseBulkMode(req.Mode, len(req.Items), maxBulkItems, "items")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.BulkResult{Mode: mode, Items: make([]model.BulkOutcome, len(req.Items))}
+	var subs []*model.Subscription
+	var positions []int
+
+	for i, item := range req.Items {
+		sub, err := newSubscription(item)
+		if err != nil {
+			result.Items[i].Err = err
+			continue
+		}
+		subs = append(subs, sub)
+		positions = append(positions, i)
+	}
+
+	err = applyBulk(result, positions, func(atomic bool) ([]error, error) {
+		return s.repo.CreateBatch(subs, s.opts.OverlapPolicy, meta, atomic)
+	})
+	if err != nil {
+		log.Printf("Error creating subscriptions in bulk: %v", err)
+		return nil, fmt.Errorf("failed to create subscriptions: %w", err)
+	}
+
+	fillBulkSubscriptions(result, positions, subs)
+	return result, nil
+}
+
+func (s *subscriptionService) BulkUpdate(req model.BulkUpdateRequest, meta model.AuditMeta) (*model.BulkResult, error) {
+	log.Printf("Updating %d subscriptions in bulk", len(req.Items))
+
+	mode, err := parseBulkMode(req.Mode, len(req.Items), maxBulkItems, "items")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.BulkResult{Mode: mode, Items: make([]model.BulkOutcome, len(req.Items))}
+	var subs []*model.Subscription
+	var versions []*uint32
+	var positions []int
+
+	for i, item := range req.Items {
+		result.Items[i].ID = item.ID
+		if item.ID == 0 {
+			result.Items[i].Err = model.NewValidationError("id", "is required")
+			continue
+		}
+
+		sub, err := newSubscription(item.SubscriptionCreateRequest)
+		if err != nil {
+			result.Items[i].Err = err
+			continue
+		}
+		sub.ID = item.ID
+
+		subs = append(subs, sub)
+		versions = append(versions, item.Version)
+		positions = append(positions, i)
+	}
+
+	err = applyBulk(result, positions, func(atomic bool) ([]error, error) {
+		return s.repo.UpdateBatch(subs, versions, s.opts.OverlapPolicy, meta, atomic)
+	})
+	if err != nil {
+		log.Printf("Error updating subscriptions in bulk: %v", err)
+		return nil, fmt.Errorf("failed to update subscriptions: %w", err)
+	}
+
+	fillBulkSubscriptions(result, positions, subs)
+	return result, nil
+}
+
+func (s *subscriptionService) BulkDelete(req model.BulkDeleteRequest, meta model.AuditMeta) (*model.BulkResult, error) {
+	log.Printf("Deleting %d subscriptions in bulk", len(req.IDs))
+
+	mode, err := parseBulkMode(req.Mode, len(req.IDs), maxBulkItems, "ids")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.BulkResult{Mode: mode, Items: make([]model.BulkOutcome, len(req.IDs))}
+	var ids []uint32
+	var positions []int
+
+	for i, id := range req.IDs {
+		result.Items[i].ID = id
+		if id == 0 {
+			result.Items[i].Err = model.NewValidationError("id", "is required")
+			continue
+		}
+		ids = append(ids, id)
+		positions = append(positions, i)
+	}
+
+	err = applyBulk(result, positions, func(atomic bool) ([]error, error) {
+		return s.repo.DeleteBatch(ids, meta, atomic)
+	})
+	if err != nil {
+		log.Printf("Error deleting subscriptions in bulk: %v", err)
+		return nil, fmt.Errorf("failed to delete subscriptions: %w", err)
+	}
+
+	return result, nil
+}
+
+// parseBulkMode проверяет режим и размер пакета из не более чем limit элементов. Пустой режим означает atomic
+func parseBulkMode(mode model.BulkMode, size, limit int, field string) (model.BulkMode, error) {
+	var fields []model.FieldError
+
+	if mode == "" {
+		mode = model.BulkAtomic
+	}
+	if !mode.Valid() {
+		fields = append(fields, model.FieldError{Field: "mode", Message: "must be one of: atomic, best_effort"})
+	}
+
+	switch {
+	case size == 0:
+		fields = append(fields, model.FieldError{Field: field, Message: "must contain at least 1 item"})
+	case size > limit:
+		fields = append(fields, model.FieldError{Field: field, Message: fmt.Sprintf("must contain at most %d items", limit)})
+	}
+
+	return mode, newValidationError(fields)
+}
+
+// applyBulk передает в репозиторий элементы, прошедшие проверку (positions - их позиции в пакете),
+// и раскладывает ошибки репозитория по элементам. Атомарный пакет с ошибками не отправляется
+// в репозиторий или откатывается целиком, а остальные его элементы помечаются ErrBatchAborted
+func applyBulk(result *model.BulkResult, positions []int, run func(atomic bool) ([]error, error)) error {
+	atomic := result.Mode == model.BulkAtomic
+
+	if len(positions) > 0 && (!atomic || len(positions) == len(result.Items)) {
+		errs, err := run(atomic)
+		if err != nil {
+			return err
+		}
+		for j, i := range positions {
+			result.Items[i].Err = errs[j]
+		}
+	}
+
+	if !atomic {
+		return nil
+	}
+
+	failed := false
+	for _, item := range result.Items {
+		if item.Err != nil {
+			failed = true
+			break
+		}
+	}
+
+	if failed {
+		for i := range result.Items {
+			if result.Items[i].Err == nil {
+				result.Items[i].Err = fmt.Errorf("item %d was not applied: %w", i, model.ErrBatchAborted)
+			}
+		}
+	}
+
+	return nil
+}
+
+// fillBulkSubscriptions сохраняет в результат подписки успешно выполненных элементов
+func fillBulkSubscriptions(result *model.BulkResult, positions []int, subs []*model.Subscription) {
+	for j, i := range positions {
+		if result.Items[i].Err == nil {
+			result.Items[i].ID = subs[j].ID
+			result.Items[i].Subscription = subs[j]
+		}
+	}
+}
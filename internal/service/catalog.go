@@ -0,0 +1,141 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/repository"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+)
+
+type catalogService struct {
+	repo repository.CatalogRepository
+}
+
+func NewCatalogService(repo repository.CatalogRepository) *catalogService {
+	return &catalogService{repo: repo}
+}
+
+func (s *catalogService) Create(req model.ServiceRequest) (*model.Service, error) {
+	log.Printf("Creating service %q", req.Name)
+
+	svc, err := newService(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(svc); err != nil {
+		log.Printf("Error creating service: %v", err)
+		return nil, fmt.Errorf("failed to create service: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (s *catalogService) GetByID(id uint32) (*model.Service, error) {
+	svc, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (s *catalogService) List(category string) ([]model.Service, error) {
+	var filter *string
+	if category != "" {
+		filter = &category
+	}
+
+	services, err := s.repo.List(filter)
+	if err != nil {
+		log.Printf("Error listing services: %v", err)
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+
+	return services, nil
+}
+
+func (s *catalogService) Update(id uint32, req model.ServiceRequest, meta model.AuditMeta) (*model.Service, error) {
+	log.Printf("Updating service with ID: %d", id)
+
+	svc, err := newService(req)
+	if err != nil {
+		return nil, err
+	}
+	svc.ID = id
+
+	if err := s.repo.Update(svc, meta); err != nil {
+		log.Printf("Error updating service %d: %v", id, err)
+		return nil, fmt.Errorf("failed to update service: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (s *catalogService) Delete(id uint32) error {
+	log.Printf("Deleting service with ID: %d", id)
+
+	if err := s.repo.Delete(id); err != nil {
+		log.Printf("Error deleting service %d: %v", id, err)
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+
+	return nil
+}
+
+// Merge объединяет сервис req.SourceID с сервисом targetID, например разные написания одного сервиса
+func (s *catalogService) Merge(targetID uint32, req model.ServiceMergeRequest, meta model.AuditMeta) (*model.Service, error) {
+	log.Printf("Merging service %d into service %d", req.SourceID, targetID)
+
+	if err := newValidationError(validator.Struct(req)); err != nil {
+		return nil, err
+	}
+	if req.SourceID == targetID {
+		return nil, model.NewValidationError("source_id", "must differ from the target service")
+	}
+
+	svc, err := s.repo.Merge(targetID, req.SourceID, meta)
+	if err != nil {
+		log.Printf("Error merging services: %v", err)
+		return nil, fmt.Errorf("failed to merge services: %w", err)
+	}
+
+	return svc, nil
+}
+
+// newService проверяет запрос и строит по нему сервис каталога с нормализованными написаниями
+func newService(req model.ServiceRequest) (*model.Service, error) {
+	if req.Currency != nil {
+		currency := strings.ToUpper(*req.Currency)
+		req.Currency = &currency
+	}
+
+	fields := validator.Struct(req)
+
+	name := strings.Join(strings.Fields(req.Name), " ")
+	if req.Name != "" && name == "" {
+		fields = append(fields, model.FieldError{Field: "name", Message: "must not be blank"})
+	}
+
+	if err := newValidationError(fields); err != nil {
+		return nil, err
+	}
+
+	aliases := make([]string, 0, len(req.Aliases))
+	for _, alias := range req.Aliases {
+		if alias = strings.Join(strings.Fields(alias), " "); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	return &model.Service{
+		Name:         name,
+		Aliases:      aliases,
+		Category:     req.Category,
+		DefaultPrice: req.DefaultPrice,
+		Currency:     req.Currency,
+	}, nil
+}
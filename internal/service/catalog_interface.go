@@ -0,0 +1,12 @@
+package service
+
+import "github.com/Fedasov/Effective-Mobile/internal/model"
+
+type CatalogService interface {
+	Create(req model.ServiceRequest) (*model.Service, error)
+	GetByID(id uint32) (*model.Service, error)
+	List(category string) ([]model.Service, error)
+	Update(id uint32, req model.ServiceRequest, meta model.AuditMeta) (*model.Service, error)
+	Delete(id uint32) error
+	Merge(targetID uint32, req model.ServiceMergeRequest, meta model.AuditMeta) (*model.Service, error)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// rateTable пересчитывает цены подписок в валюту отчета по курсам, загруженным на период отчета
+type rateTable struct {
+	target string
+	// Курсы каждой валюты по возрастанию месяца
+	rates map[string][]model.ExchangeRate
+}
+
+// reportCurrency возвращает валюту отчета из запроса или DefaultCurrency
+func reportCurrency(currency *string) string {
+	if currency == nil || *currency == "" {
+		return model.DefaultCurrency
+	}
+
+	return strings.ToUpper(*currency)
+}
+
+// loadRates загружает курсы валют подписок и валюты отчета, действующие до месяца to включительно.
+// Если все подписки в валюте отчета, курсы не запрашиваются
+func (s *subscriptionService) loadRates(target string, subscriptions []model.Subscription, to time.Time) (*rateTable, error) {
+	table := &rateTable{target: target, rates: make(map[string][]model.ExchangeRate)}
+
+	seen := make(map[string]bool)
+	var currencies []string
+	for _, sub := range subscriptions {
+		if sub.Currency != target && !seen[sub.Currency] {
+			seen[sub.Currency] = true
+			currencies = append(currencies, sub.Currency)
+		}
+	}
+	if len(currencies) == 0 {
+		return table, nil
+	}
+	if target != model.DefaultCurrency {
+		currencies = append(currencies, target)
+	}
+
+	rates, err := s.rates.ListUpTo(currencies, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load exchange rates: %w", err)
+	}
+
+	for _, rate := range rates {
+		table.rates[rate.Currency] = append(table.rates[rate.Currency], rate)
+	}
+
+	return table, nil
+}
+
+// rate возвращает стоимость единицы валюты в DefaultCurrency по последнему курсу не позже месяца month
+func (t *rateTable) rate(currency string, month time.Time) (model.Rate, error) {
+	if currency == model.DefaultCurrency {
+		return model.RateOne, nil
+	}
+
+	rates := t.rates[currency]
+	i := sort.Search(len(rates), func(i int) bool {
+		return rates[i].Month.After(month)
+	})
+	if i == 0 {
+		return 0, fmt.Errorf("no exchange rate for %s in %s: %w", currency, month.Format(monthLayout), model.ErrConstraint)
+	}
+
+	return rates[i-1].Rate, nil
+}
+
+// convert пересчитывает сумму в валюту отчета по курсу месяца month с однократным округлением до минимальной единицы
+func (t *rateTable) convert(money model.Money, month time.Time) (model.Amount, error) {
+	if money.Currency == t.target {
+		return money.Amount, nil
+	}
+
+	from, err := t.rate(money.Currency, month)
+	if err != nil {
+		return 0, err
+	}
+
+	to, err := t.rate(t.target, month)
+	if err != nil {
+		return 0, err
+	}
+
+	return money.Amount.Convert(from, to)
+}
+
+// billedCost возвращает стоимость подписки за период [from, to] в валюте отчета.
+// Каждое списание пересчитывается по курсу своего месяца
+func (t *rateTable) billedCost(sub model.Subscription, from, to time.Time, prorate bool) (model.Amount, error) {
+	charges, err := subscriptionCharges(sub, from, lastDayOfMonth(to), prorate)
+	if err != nil {
+		return 0, err
+	}
+
+	var total costSum
+	for _, c := range charges {
+		amount, err := t.convert(model.Money{Amount: c.amount, Currency: sub.Currency}, c.date)
+		if err != nil {
+			return 0, err
+		}
+		total.add(amount)
+	}
+
+	return total.result()
+}
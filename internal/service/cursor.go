@@ -0,0 +1,71 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// encodeCursor формирует непрозрачный курсор по последней записи страницы
+func encodeCursor(sub model.Subscription, sortBy string, sortDesc bool) (string, error) {
+	cursor := model.SubscriptionCursor{
+		SortBy:   sortBy,
+		SortDesc: sortDesc,
+		Value:    sortValue(sub, sortBy),
+		ID:       sub.ID,
+	}
+
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor разбирает курсор, полученный от клиента
+func decodeCursor(value string) (*model.SubscriptionCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, model.NewValidationError("cursor", "is invalid")
+	}
+
+	var cursor model.SubscriptionCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, model.NewValidationError("cursor", "is invalid")
+	}
+
+	if cursor.SortBy != "" && !isSortField(cursor.SortBy) {
+		return nil, model.NewValidationError("cursor", "is invalid")
+	}
+
+	return &cursor, nil
+}
+
+// sortValue возвращает значение поля сортировки записи в текстовом виде для сравнения в SQL
+func sortValue(sub model.Subscription, sortBy string) *string {
+	var value string
+
+	switch sortBy {
+	case "service_name":
+		value = sub.ServiceName
+	case "price":
+		value = strconv.FormatInt(int64(sub.Price), 10)
+	case "user_id":
+		value = sub.UserID.String()
+	case "start_date":
+		value = sub.StartDate.Format("2006-01-02")
+	case "end_date":
+		if sub.EndDate == nil {
+			return nil
+		}
+		value = sub.EndDate.Format("2006-01-02")
+	default:
+		value = strconv.FormatUint(uint64(sub.ID), 10)
+	}
+
+	return &value
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// Export передает fn все подписки, подходящие под фильтры списка, в порядке сортировки запроса.
+// Параметры пагинации не учитываются, подписки читаются из базы потоком
+func (s *subscriptionService) Export(req model.SubscriptionListRequest, fn func(*model.Subscription) error) error {
+	log.Printf("Exporting subscriptions")
+
+	req.Limit, req.Offset = 0, 0
+	req.Cursor, req.UseCursor = "", false
+
+	filter, err := parseListFilter(req)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Stream(*filter, fn); err != nil {
+		log.Printf("Error exporting subscriptions: %v", err)
+		return fmt.Errorf("failed to export subscriptions: %w", err)
+	}
+
+	return nil
+}
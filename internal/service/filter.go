@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/google/uuid"
+)
+
+// parseListFilter проверяет параметры запроса списка и преобразует их в условия выборки
+func parseListFilter(req model.SubscriptionListRequest) (*model.SubscriptionFilter, error) {
+	filter := &model.SubscriptionFilter{
+		SortBy:         "id",
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+		IncludeDeleted: req.IncludeDeleted,
+	}
+
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
+			return nil, model.NewValidationError("user_id", "must be a valid UUID")
+		}
+		filter.UserID = &userID
+	}
+
+	if req.ServiceName != "" {
+		filter.ServiceName = &req.ServiceName
+	}
+
+	if req.ServiceNamePrefix != "" {
+		filter.ServiceNamePrefix = &req.ServiceNamePrefix
+	}
+
+	var err error
+	if filter.MinPrice, err = parseOptionalPrice("min_price", req.MinPrice); err != nil {
+		return nil, err
+	}
+	if filter.MaxPrice, err = parseOptionalPrice("max_price", req.MaxPrice); err != nil {
+		return nil, err
+	}
+
+	// Месяц без дня в верхних границах означает его последнее число, чтобы граница включала весь месяц
+	dates := []struct {
+		name   string
+		value  string
+		parse  func(string) (time.Time, error)
+		target **time.Time
+	}{
+		{"active_at", req.ActiveAt, parseStartDate, &filter.ActiveAt},
+		{"start_from", req.StartFrom, parseStartDate, &filter.StartFrom},
+		{"start_to", req.StartTo, parseEndDate, &filter.StartTo},
+		{"end_from", req.EndFrom, parseStartDate, &filter.EndFrom},
+		{"end_to", req.EndTo, parseEndDate, &filter.EndTo},
+	}
+	for _, d := range dates {
+		if d.value == "" {
+			continue
+		}
+
+		date, err := d.parse(d.value)
+		if err != nil {
+			return nil, model.NewValidationError(d.name, dateFormatMessage)
+		}
+		*d.target = &date
+	}
+
+	filter.Today = today()
+	filter.InTrial = req.InTrial
+	if req.TrialEndsWithin != "" {
+		days, err := strconv.Atoi(req.TrialEndsWithin)
+		if err != nil || days < 0 || days > maxTrialDays {
+			return nil, model.NewValidationError("trial_ends_within", fmt.Sprintf("must be an integer from 0 to %d", maxTrialDays))
+		}
+		trialEndsBy := filter.Today.AddDate(0, 0, days)
+		filter.TrialEndsBy = &trialEndsBy
+	}
+
+	if req.Sort != "" {
+		if !isSortField(req.Sort) {
+			return nil, model.NewValidationError("sort", "must be one of "+strings.Join(model.SubscriptionSortFields, ", "))
+		}
+		filter.SortBy = req.Sort
+	}
+
+	switch req.Order {
+	case "", model.SortOrderAsc:
+	case model.SortOrderDesc:
+		filter.SortDesc = true
+	default:
+		return nil, model.NewValidationError("order", "must be asc or desc")
+	}
+
+	if req.UseCursor {
+		if err := applyCursor(filter, req); err != nil {
+			return nil, err
+		}
+	}
+
+	return filter, nil
+}
+
+// applyCursor переводит фильтр в режим keyset-пагинации. Сортировка берется из курсора,
+// чтобы страницы одной выборки оставались согласованными
+func applyCursor(filter *model.SubscriptionFilter, req model.SubscriptionListRequest) error {
+	if req.Offset != 0 {
+		return model.NewValidationError("offset", "cannot be combined with cursor")
+	}
+
+	if req.Limit <= 0 {
+		return model.NewValidationError("limit", "must be positive")
+	}
+
+	if req.Cursor == "" {
+		return nil
+	}
+
+	cursor, err := decodeCursor(req.Cursor)
+	if err != nil {
+		return err
+	}
+
+	if (req.Sort != "" && req.Sort != cursor.SortBy) || (req.Order != "" && filter.SortDesc != cursor.SortDesc) {
+		return model.NewValidationError("cursor", "sort and order must match the cursor")
+	}
+
+	filter.SortBy = cursor.SortBy
+	filter.SortDesc = cursor.SortDesc
+	filter.After = cursor
+
+	return nil
+}
+
+// parseOptionalPrice разбирает необязательную границу цены
+func parseOptionalPrice(name, value string) (*model.Amount, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	price, err := model.ParseAmount(value)
+	if err != nil {
+		return nil, model.NewValidationError(name, err.Error())
+	}
+
+	return &price, nil
+}
+
+// isSortField проверяет, что по полю разрешена сортировка
+func isSortField(field string) bool {
+	for _, f := range model.SubscriptionSortFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// maxIdempotencyKeyLength ограничивает длину ключа идемпотентности
+const maxIdempotencyKeyLength = 255
+
+// hashRequest вычисляет отпечаток тела запроса, чтобы обнаружить повторное использование ключа
+// с другими данными
+func hashRequest(req model.SubscriptionCreateRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash request: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
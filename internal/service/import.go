@@ -0,0 +1,271 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/google/uuid"
+)
+
+// maxImportRows ограничивает количество строк в одном импортируемом файле
+const maxImportRows = 5000
+
+// importColumns - колонки CSV-файла импорта; обязательны только колонки requiredImportColumns
+var importColumns = []string{"service_name", "price", "currency", "billing_period", "billing_interval", "anchor_day", "trial_days",
+	"user_id", "start_date", "end_date"}
+
+// requiredImportColumns - колонки, без которых файл импорта не принимается
+var requiredImportColumns = []string{"service_name", "price", "user_id", "start_date"}
+
+// importRow - строка CSV-файла с номером строки в файле
+type importRow struct {
+	line   int
+	fields map[string]string
+}
+
+// ImportCSV создает подписки из CSV-файла с заголовком. В режиме dryRun строки только проверяются,
+// и в результате возвращаются ошибки каждой строки; иначе корректные строки сохраняются в режиме mode
+func (s *subscriptionService) ImportCSV(src io.Reader, mode model.BulkMode, dryRun bool, meta model.AuditMeta) (*model.ImportResult, error) {
+	rows, err := readImportCSV(src)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Importing %d subscriptions from CSV (dry run: %t)", len(rows), dryRun)
+
+	// Размер файла уже ограничен при чтении: импорт больше обычного пакета, поэтому лимит свой
+	mode, err = parseBulkMode(mode, len(rows), maxImportRows, "file")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.ImportResult{
+		BulkResult: model.BulkResult{Mode: mode, Items: make([]model.BulkOutcome, len(rows))},
+		DryRun:     dryRun,
+	}
+	var subs []*model.Subscription
+	var positions []int
+
+	for i, row := range rows {
+		result.Items[i].Line = row.line
+
+		sub, err := parseImportRow(row)
+		if err != nil {
+			result.Items[i].Err = err
+			continue
+		}
+
+		if dryRun {
+			result.Items[i].Subscription = sub
+			continue
+		}
+		subs = append(subs, sub)
+		positions = append(positions, i)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	err = applyBulk(&result.BulkResult, positions, func(atomic bool) ([]error, error) {
+		return s.repo.CreateBatch(subs, s.opts.OverlapPolicy, meta, atomic)
+	})
+	if err != nil {
+		log.Printf("Error importing subscriptions: %v", err)
+		return nil, fmt.Errorf("failed to import subscriptions: %w", err)
+	}
+
+	fillBulkSubscriptions(&result.BulkResult, positions, subs)
+	return result, nil
+}
+
+// readImportCSV читает заголовок и строки файла. Ошибки структуры файла возвращаются
+// как ошибка валидации поля file, ошибки отдельных строк проверяются позже
+func readImportCSV(src io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(src)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, model.NewValidationError("file", "must contain a header row")
+		}
+		return nil, model.NewValidationError("file", err.Error())
+	}
+
+	columns, err := parseImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, model.NewValidationError("file", err.Error())
+		}
+
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+
+		if len(rows) == maxImportRows {
+			return nil, model.NewValidationError("file", fmt.Sprintf("must contain at most %d items", maxImportRows))
+		}
+
+		row := importRow{line: line, fields: make(map[string]string, len(columns))}
+		if len(record) != len(columns) {
+			row.fields = nil
+		} else {
+			for i, column := range columns {
+				row.fields[column] = strings.TrimSpace(record[i])
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseImportHeader проверяет заголовок файла и возвращает имена колонок по порядку
+func parseImportHeader(header []string) ([]string, error) {
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !isImportColumn(name) {
+			return nil, model.NewValidationError("file", fmt.Sprintf("unknown column %q, expected: %s", name, strings.Join(importColumns, ", ")))
+		}
+		if seen[name] {
+			return nil, model.NewValidationError("file", fmt.Sprintf("duplicate column %q", name))
+		}
+		seen[name] = true
+		columns[i] = name
+	}
+
+	var missing []string
+	for _, name := range requiredImportColumns {
+		if !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, model.NewValidationError("file", "missing columns: "+strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+// parseImportRow разбирает строку файла и проверяет ее так же, как запрос на создание подписки
+func parseImportRow(row importRow) (*model.Subscription, error) {
+	if row.fields == nil {
+		return nil, model.NewValidationError("row", "number of fields does not match the header")
+	}
+
+	req := model.SubscriptionCreateRequest{
+		ServiceName: row.fields["service_name"],
+		StartDate:   row.fields["start_date"],
+	}
+	if endDate := row.fields["end_date"]; endDate != "" {
+		req.EndDate = &endDate
+	}
+	if currency := row.fields["currency"]; currency != "" {
+		req.Currency = &currency
+	}
+	if billingPeriod := row.fields["billing_period"]; billingPeriod != "" {
+		req.BillingPeriod = &billingPeriod
+	}
+
+	var fields []model.FieldError
+
+	var trialDays *int32
+	numbers := []struct {
+		name   string
+		target **int32
+	}{
+		{"billing_interval", &req.BillingInterval},
+		{"anchor_day", &req.AnchorDay},
+		{"trial_days", &trialDays},
+	}
+	for _, n := range numbers {
+		value := row.fields[n.name]
+		if value == "" {
+			continue
+		}
+
+		number, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			fields = append(fields, model.FieldError{Field: n.name, Message: "must be an integer"})
+			continue
+		}
+		parsed := int32(number)
+		*n.target = &parsed
+	}
+	if trialDays != nil {
+		req.TrialDays = *trialDays
+	}
+
+	if value := row.fields["price"]; value != "" {
+		price, err := model.ParseAmount(value)
+		if err != nil {
+			fields = append(fields, model.FieldError{Field: "price", Message: err.Error()})
+		}
+		req.Price = price
+	}
+
+	if value := row.fields["user_id"]; value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			fields = append(fields, model.FieldError{Field: "user_id", Message: "must be a valid UUID"})
+		}
+		req.UserID = userID
+	}
+
+	if len(fields) == 0 {
+		return newSubscription(req)
+	}
+
+	// Остальные поля проверяются как обычно, чтобы вернуть все ошибки строки сразу
+	var validationErr *model.ValidationError
+	if errors.As(validateSubscriptionRequest(req), &validationErr) {
+		for _, f := range validationErr.Fields {
+			if !hasFieldError(fields, f.Field) {
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	return nil, newValidationError(fields)
+}
+
+func isImportColumn(name string) bool {
+	for _, column := range importColumns {
+		if column == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+
+	return true
+}
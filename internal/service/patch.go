@@ -0,0 +1,151 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// mergePatch накладывает изменения на текущее состояние подписки и возвращает полный запрос,
+// чтобы проверить результат теми же правилами, что и при создании
+func mergePatch(existing model.Subscription, req model.SubscriptionPatchRequest) (model.SubscriptionCreateRequest, error) {
+	currency := existing.Currency
+	billingPeriod, billingInterval, anchorDay := existing.BillingPeriod, existing.BillingInterval, existing.AnchorDay
+	merged := model.SubscriptionCreateRequest{
+		ServiceName:     existing.ServiceName,
+		Price:           existing.Price,
+		Currency:        &currency,
+		BillingPeriod:   &billingPeriod,
+		BillingInterval: &billingInterval,
+		AnchorDay:       &anchorDay,
+		TrialDays:       existing.TrialDays,
+		UserID:          existing.UserID,
+		StartDate:       existing.StartDate.Format(dateLayout),
+	}
+	if existing.EndDate != nil {
+		endDate := existing.EndDate.Format(dateLayout)
+		merged.EndDate = &endDate
+	}
+
+	var fields []model.FieldError
+	notNullable := func(field string, set, null bool) bool {
+		if set && null {
+			fields = append(fields, model.FieldError{Field: field, Message: "cannot be null"})
+			return false
+		}
+		return set
+	}
+
+	if notNullable("service_id", req.ServiceID.Set, req.ServiceID.Null) {
+		merged.ServiceID = &req.ServiceID.Value
+	}
+	if notNullable("service_name", req.ServiceName.Set, req.ServiceName.Null) {
+		merged.ServiceName = req.ServiceName.Value
+	}
+	if notNullable("price", req.Price.Set, req.Price.Null) {
+		merged.Price = req.Price.Value
+	}
+	if notNullable("currency", req.Currency.Set, req.Currency.Null) {
+		merged.Currency = &req.Currency.Value
+	}
+	if notNullable("billing_period", req.BillingPeriod.Set, req.BillingPeriod.Null) {
+		merged.BillingPeriod = &req.BillingPeriod.Value
+		// Интервал и день списания прежней периодичности могут не подходить к новой
+		merged.BillingInterval = nil
+		merged.AnchorDay = nil
+	}
+	if notNullable("billing_interval", req.BillingInterval.Set, req.BillingInterval.Null) {
+		merged.BillingInterval = &req.BillingInterval.Value
+	}
+	if req.AnchorDay.Set {
+		if req.AnchorDay.Null {
+			merged.AnchorDay = nil
+		} else {
+			anchorDay := req.AnchorDay.Value
+			merged.AnchorDay = &anchorDay
+		}
+	}
+	if notNullable("trial_days", req.TrialDays.Set, req.TrialDays.Null) {
+		merged.TrialDays = req.TrialDays.Value
+	}
+	if notNullable("user_id", req.UserID.Set, req.UserID.Null) {
+		merged.UserID = req.UserID.Value
+	}
+	if notNullable("start_date", req.StartDate.Set, req.StartDate.Null) {
+		merged.StartDate = req.StartDate.Value
+	}
+	if req.EndDate.Set {
+		if req.EndDate.Null {
+			merged.EndDate = nil
+		} else {
+			endDate := req.EndDate.Value
+			merged.EndDate = &endDate
+		}
+	}
+
+	return merged, newValidationError(fields)
+}
+
+// diffPatch оставляет в изменениях только поля, значения которых отличаются от текущих
+func diffPatch(existing model.Subscription, merged model.SubscriptionCreateRequest) (model.SubscriptionPatch, error) {
+	var patch model.SubscriptionPatch
+
+	// Название сравнивается, только если сервис не задан по ID: ID имеет приоритет
+	if merged.ServiceID != nil {
+		if *merged.ServiceID != existing.ServiceID {
+			patch.ServiceID = merged.ServiceID
+		}
+	} else if merged.ServiceName != existing.ServiceName {
+		patch.ServiceName = &merged.ServiceName
+	}
+	if merged.Price != existing.Price {
+		patch.Price = &merged.Price
+	}
+	if mergedCurrency := requestCurrency(merged); mergedCurrency != existing.Currency {
+		patch.Currency = &mergedCurrency
+	}
+	if merged.TrialDays != existing.TrialDays {
+		patch.TrialDays = &merged.TrialDays
+	}
+	if merged.UserID != existing.UserID {
+		patch.UserID = &merged.UserID
+	}
+
+	startDate, err := parseStartDate(merged.StartDate)
+	if err != nil {
+		return patch, model.NewValidationError("start_date", dateFormatMessage)
+	}
+	if !startDate.Equal(existing.StartDate) {
+		patch.StartDate = &startDate
+	}
+
+	period, interval, anchorDay := requestBilling(merged, startDate)
+	if period != existing.BillingPeriod || interval != existing.BillingInterval || anchorDay != existing.AnchorDay {
+		patch.BillingPeriod = &period
+		patch.BillingInterval = &interval
+		patch.AnchorDay = &anchorDay
+	}
+
+	var endDate *time.Time
+	if merged.EndDate != nil {
+		parsed, err := parseEndDate(*merged.EndDate)
+		if err != nil {
+			return patch, model.NewValidationError("end_date", dateFormatMessage)
+		}
+		endDate = &parsed
+	}
+	if !sameDate(endDate, existing.EndDate) {
+		patch.EndDateSet = true
+		patch.EndDate = endDate
+	}
+
+	return patch, nil
+}
+
+func sameDate(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	return a.Equal(*b)
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+)
+
+// PriceSchedule возвращает цены подписки: базовую с месяца начала и последующие изменения
+func (s *subscriptionService) PriceSchedule(id uint32) ([]model.PriceChange, error) {
+	log.Printf("Getting price schedule of subscription with ID: %d", id)
+
+	subscription, err := s.repo.GetByID(id, false)
+	if err != nil {
+		log.Printf("Error getting subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	changes, err := s.repo.ListPriceChanges(id)
+	if err != nil {
+		log.Printf("Error getting price changes of subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get price changes: %w", err)
+	}
+
+	schedule := make([]model.PriceChange, 0, len(changes)+1)
+	schedule = append(schedule, model.PriceChange{EffectiveFrom: subscription.StartDate, Price: subscription.Price})
+	schedule = append(schedule, changes...)
+
+	return schedule, nil
+}
+
+// SchedulePriceChange планирует новую цену подписки начиная с месяца req.EffectiveFrom.
+// Уже начавшиеся месяцы не пересчитываются, поэтому изменение возможно только со следующего месяца или позже
+func (s *subscriptionService) SchedulePriceChange(id uint32, req model.PriceChangeRequest, expectedVersion *uint32, meta model.AuditMeta) ([]model.PriceChange, error) {
+	log.Printf("Scheduling price change of subscription %d from %s", id, req.EffectiveFrom)
+
+	subscription, err := s.repo.GetByID(id, false)
+	if err != nil {
+		log.Printf("Error getting subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if expectedVersion != nil && *expectedVersion != subscription.Version {
+		return nil, fmt.Errorf("subscription with ID %d was modified: %w", id, model.ErrPreconditionFailed)
+	}
+
+	fields := validator.Struct(req)
+	if !hasFieldError(fields, "effective_from") {
+		effectiveFrom, _ := parseMonthYear(req.EffectiveFrom)
+		fields = append(fields, checkPriceChangeMonth(*subscription, effectiveFrom)...)
+	}
+	if err := newValidationError(fields); err != nil {
+		return nil, err
+	}
+
+	effectiveFrom, _ := parseMonthYear(req.EffectiveFrom)
+	change := model.PriceChange{EffectiveFrom: effectiveFrom, Price: req.Price}
+
+	// Версия защищает от изменения дат подписки между проверкой и сохранением
+	if err := s.repo.SchedulePriceChange(id, change, &subscription.Version, meta); err != nil {
+		log.Printf("Error scheduling price change of subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to schedule price change: %w", err)
+	}
+
+	log.Printf("Price change of subscription %d from %s scheduled successfully", id, req.EffectiveFrom)
+	return s.PriceSchedule(id)
+}
+
+// CancelPriceChange отменяет изменение цены, которое еще не вступило в силу
+func (s *subscriptionService) CancelPriceChange(id uint32, month string, expectedVersion *uint32, meta model.AuditMeta) error {
+	log.Printf("Canceling price change of subscription %d from %s", id, month)
+
+	effectiveFrom, err := parseMonthYear(month)
+	if err != nil {
+		return model.NewValidationError("month", "must be in MM-YYYY format")
+	}
+	if !effectiveFrom.After(currentMonth()) {
+		return model.NewValidationError("month", "must be a future month")
+	}
+
+	if err := s.repo.CancelPriceChange(id, effectiveFrom, expectedVersion, meta); err != nil {
+		log.Printf("Error canceling price change of subscription %d: %v", id, err)
+		return fmt.Errorf("failed to cancel price change: %w", err)
+	}
+
+	log.Printf("Price change of subscription %d from %s canceled successfully", id, month)
+	return nil
+}
+
+// checkPriceChangeMonth проверяет, что изменение цены попадает в период подписки и не затрагивает
+// прошедшие и текущий месяцы, списания в которых уже могли быть выполнены
+func checkPriceChangeMonth(sub model.Subscription, effectiveFrom time.Time) []model.FieldError {
+	switch {
+	case !effectiveFrom.After(currentMonth()):
+		return []model.FieldError{{Field: "effective_from", Message: "must be a future month"}}
+	case !effectiveFrom.After(sub.StartDate):
+		return []model.FieldError{{Field: "effective_from", Message: "must be after start_date"}}
+	case sub.EndDate != nil && effectiveFrom.After(*sub.EndDate):
+		return []model.FieldError{{Field: "effective_from", Message: "must not be after end_date"}}
+	}
+
+	return nil
+}
+
+// currentMonth возвращает первое число текущего месяца в UTC
+func currentMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// today возвращает текущую дату в UTC без времени
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// priceAt возвращает цену подписки, действующую в месяце month
+func priceAt(sub model.Subscription, month time.Time) model.Amount {
+	price := sub.Price
+	for _, change := range sub.PriceChanges {
+		if change.EffectiveFrom.After(month) {
+			break
+		}
+		price = change.Price
+	}
+
+	return price
+}
+
+// billedCost возвращает стоимость подписки за период [from, to] и количество месяцев подписки в нем.
+// Учитываются списания по периодичности подписки с датами в этих месяцах, каждое по цене на дату списания
+func billedCost(sub model.Subscription, from, to time.Time, prorate bool) (model.Amount, int32, error) {
+	months := billedMonths(sub, from, to)
+
+	charges, err := subscriptionCharges(sub, from, lastDayOfMonth(to), prorate)
+	if err != nil {
+		return 0, months, err
+	}
+
+	var cost costSum
+	for _, c := range charges {
+		cost.add(c.amount)
+	}
+
+	total, err := cost.result()
+	return total, months, err
+}
+
+// costSum складывает суммы с проверкой переполнения. После первой ошибки слагаемые
+// игнорируются, а ошибка возвращается из result
+type costSum struct {
+	amount model.Amount
+	err    error
+}
+
+func (s *costSum) add(amount model.Amount) {
+	if s.err == nil {
+		s.amount, s.err = s.amount.Add(amount)
+	}
+}
+
+func (s *costSum) result() (model.Amount, error) {
+	return s.amount, s.err
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+)
+
+// maxTrialDays - наибольшая длина пробного периода, как в ограничении таблицы subscriptions
+const maxTrialDays = 365
+
+// Promotions возвращает промо-цены подписки по возрастанию даты начала
+func (s *subscriptionService) Promotions(id uint32) ([]model.Promotion, error) {
+	log.Printf("Getting promotions of subscription with ID: %d", id)
+
+	if _, err := s.repo.GetByID(id, false); err != nil {
+		log.Printf("Error getting subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	promotions, err := s.repo.ListPromotions(id)
+	if err != nil {
+		log.Printf("Error getting promotions of subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get promotions: %w", err)
+	}
+
+	return promotions, nil
+}
+
+// AddPromotion добавляет промо-цену, которая действует в периоде подписки вместо обычной цены
+func (s *subscriptionService) AddPromotion(id uint32, req model.PromotionRequest, expectedVersion *uint32, meta model.AuditMeta) (*model.Promotion, error) {
+	log.Printf("Adding promotion to subscription %d from %s to %s", id, req.StartDate, req.EndDate)
+
+	subscription, err := s.repo.GetByID(id, false)
+	if err != nil {
+		log.Printf("Error getting subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if expectedVersion != nil && *expectedVersion != subscription.Version {
+		return nil, fmt.Errorf("subscription with ID %d was modified: %w", id, model.ErrPreconditionFailed)
+	}
+
+	fields := validator.Struct(req)
+	if !hasFieldError(fields, "start_date") && !hasFieldError(fields, "end_date") {
+		fields = append(fields, checkDateOrder(req.StartDate, req.EndDate)...)
+		fields = append(fields, checkPromotionPeriod(*subscription, req)...)
+	}
+	if err := newValidationError(fields); err != nil {
+		return nil, err
+	}
+
+	startDate, _ := parseStartDate(req.StartDate)
+	endDate, _ := parseEndDate(req.EndDate)
+	promo := &model.Promotion{StartDate: startDate, EndDate: endDate, Price: req.Price}
+
+	// Версия защищает от изменения дат подписки между проверкой и сохранением
+	if err := s.repo.AddPromotion(id, promo, &subscription.Version, meta); err != nil {
+		log.Printf("Error adding promotion to subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to add promotion: %w", err)
+	}
+
+	log.Printf("Promotion %d added to subscription %d successfully", promo.ID, id)
+	return promo, nil
+}
+
+// DeletePromotion удаляет промо-цену подписки, которая еще не началась
+func (s *subscriptionService) DeletePromotion(id uint32, promotionID uint32, expectedVersion *uint32, meta model.AuditMeta) error {
+	log.Printf("Deleting promotion %d of subscription %d", promotionID, id)
+
+	promotions, err := s.Promotions(id)
+	if err != nil {
+		return err
+	}
+	for _, promo := range promotions {
+		if promo.ID == promotionID && !startsInFutureMonth(promo.StartDate) {
+			return model.NewValidationError("promotion_id", "must start in a future month")
+		}
+	}
+
+	if err := s.repo.DeletePromotion(id, promotionID, expectedVersion, meta); err != nil {
+		log.Printf("Error deleting promotion %d of subscription %d: %v", promotionID, id, err)
+		return fmt.Errorf("failed to delete promotion: %w", err)
+	}
+
+	log.Printf("Promotion %d of subscription %d deleted successfully", promotionID, id)
+	return nil
+}
+
+// checkPromotionPeriod проверяет, что акция начинается в периоде подписки и, как изменение цены,
+// не раньше следующего месяца: списания прошедших и текущего месяцев не пересчитываются.
+// Даты запроса должны быть уже проверены на формат
+func checkPromotionPeriod(sub model.Subscription, req model.PromotionRequest) []model.FieldError {
+	startDate, _ := parseStartDate(req.StartDate)
+
+	switch {
+	case !startsInFutureMonth(startDate):
+		return []model.FieldError{{Field: "start_date", Message: "must be in a future month"}}
+	case startDate.Before(sub.StartDate):
+		return []model.FieldError{{Field: "start_date", Message: "must not be before start_date of the subscription"}}
+	case sub.EndDate != nil && startDate.After(*sub.EndDate):
+		return []model.FieldError{{Field: "start_date", Message: "must not be after end_date of the subscription"}}
+	}
+
+	return nil
+}
+
+// startsInFutureMonth сообщает, что дата приходится на месяц после текущего или позже
+func startsInFutureMonth(date time.Time) bool {
+	return !date.Before(currentMonth().AddDate(0, 1, 0))
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/repository"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+)
+
+// maxRateItems - наибольшее количество курсов в одном запросе или файле
+const maxRateItems = 5000
+
+// rateColumns - колонки CSV-файла курсов
+var rateColumns = []string{"currency", "month", "rate"}
+
+type rateService struct {
+	repo repository.RateRepository
+}
+
+func NewRateService(repo repository.RateRepository) *rateService {
+	return &rateService{repo: repo}
+}
+
+// Upsert проверяет и сохраняет курсы. Если хотя бы один курс некорректен, ничего не сохраняется
+func (s *rateService) Upsert(reqs []model.ExchangeRateRequest) ([]model.ExchangeRate, error) {
+	log.Printf("Storing %d exchange rates", len(reqs))
+
+	labels := make([]string, len(reqs))
+	for i := range reqs {
+		labels[i] = fmt.Sprintf("[%d]", i)
+	}
+
+	return s.store(reqs, labels)
+}
+
+// ImportCSV загружает курсы из CSV-файла с заголовком currency,month,rate
+func (s *rateService) ImportCSV(src io.Reader) (int, error) {
+	reqs, labels, err := readRatesCSV(src)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Printf("Importing %d exchange rates", len(reqs))
+
+	rates, err := s.store(reqs, labels)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(rates), nil
+}
+
+func (s *rateService) List(currency string) ([]model.ExchangeRate, error) {
+	var filter *string
+	if currency != "" {
+		currency = strings.ToUpper(currency)
+		filter = &currency
+	}
+
+	rates, err := s.repo.List(filter)
+	if err != nil {
+		log.Printf("Error listing exchange rates: %v", err)
+		return nil, fmt.Errorf("failed to list exchange rates: %w", err)
+	}
+
+	return rates, nil
+}
+
+// store проверяет курсы и сохраняет их одной транзакцией. labels задают префиксы полей
+// в ошибках валидации, чтобы клиент мог найти некорректный элемент или строку файла
+func (s *rateService) store(reqs []model.ExchangeRateRequest, labels []string) ([]model.ExchangeRate, error) {
+	if len(reqs) == 0 {
+		return nil, model.NewValidationError("rates", "must not be empty")
+	}
+	if len(reqs) > maxRateItems {
+		return nil, model.NewValidationError("rates", fmt.Sprintf("must contain at most %d items", maxRateItems))
+	}
+
+	var fields []model.FieldError
+	rates := make([]model.ExchangeRate, 0, len(reqs))
+	for i, req := range reqs {
+		req.Currency = strings.ToUpper(req.Currency)
+		itemFields := validator.Struct(req)
+		if !hasFieldError(itemFields, "currency") && req.Currency == model.DefaultCurrency {
+			itemFields = append(itemFields, model.FieldError{Field: "currency", Message: "must not be the base currency " + model.DefaultCurrency})
+		}
+		for _, f := range itemFields {
+			fields = append(fields, model.FieldError{Field: labels[i] + "." + f.Field, Message: f.Message})
+		}
+		if len(itemFields) > 0 {
+			continue
+		}
+
+		month, _ := parseMonthYear(req.Month)
+		rates = append(rates, model.ExchangeRate{Currency: req.Currency, Month: month, Rate: req.Rate})
+	}
+	if err := newValidationError(fields); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Upsert(rates); err != nil {
+		log.Printf("Error storing exchange rates: %v", err)
+		return nil, fmt.Errorf("failed to store exchange rates: %w", err)
+	}
+
+	log.Printf("Stored %d exchange rates", len(rates))
+	return rates, nil
+}
+
+// readRatesCSV читает курсы из CSV-файла и возвращает их вместе с номерами строк файла
+func readRatesCSV(src io.Reader) ([]model.ExchangeRateRequest, []string, error) {
+	reader := csv.NewReader(src)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, model.NewValidationError("file", "must contain a header row")
+		}
+		return nil, nil, model.NewValidationError("file", err.Error())
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, string(rune(0xFEFF)))))] = i
+	}
+	for _, column := range rateColumns {
+		if _, ok := positions[column]; !ok || len(header) != len(rateColumns) {
+			return nil, nil, model.NewValidationError("file", "header must be: "+strings.Join(rateColumns, ","))
+		}
+	}
+
+	var reqs []model.ExchangeRateRequest
+	var labels []string
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, model.NewValidationError("file", err.Error())
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		if len(reqs) == maxRateItems {
+			return nil, nil, model.NewValidationError("file", fmt.Sprintf("must contain at most %d rows", maxRateItems))
+		}
+
+		line, _ := reader.FieldPos(0)
+		label := fmt.Sprintf("line %d", line)
+
+		rate, err := model.ParseRate(record[positions["rate"]])
+		if err != nil {
+			return nil, nil, model.NewValidationError(label+".rate", err.Error())
+		}
+
+		reqs = append(reqs, model.ExchangeRateRequest{
+			Currency: strings.TrimSpace(record[positions["currency"]]),
+			Month:    strings.TrimSpace(record[positions["month"]]),
+			Rate:     rate,
+		})
+		labels = append(labels, label)
+	}
+
+	return reqs, labels, nil
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"io"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+type RateService interface {
+	Upsert(reqs []model.ExchangeRateRequest) ([]model.ExchangeRate, error)
+	ImportCSV(src io.Reader) (int, error)
+	List(currency string) ([]model.ExchangeRate, error)
+}
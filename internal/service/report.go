@@ -0,0 +1,301 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+)
+
+// maxBreakdownMonths ограничивает длину периода помесячного отчета, чтобы один запрос
+// не перебирал списания за неограниченное число месяцев
+const maxBreakdownMonths = 120
+
+// monthCharge - списание подписки subscriptions[sub], отнесенное к месяцу своей даты
+type monthCharge struct {
+	sub    int
+	amount model.Amount
+}
+
+// monthKey возвращает порядковый номер месяца даты для группировки списаний по месяцам
+func monthKey(date time.Time) int {
+	return date.Year()*12 + int(date.Month()) - 1
+}
+
+func (s *subscriptionService) CostBreakdown(req model.CostBreakdownRequest) (*model.CostBreakdownResponse, error) {
+	log.Printf("Calculating cost breakdown for period %s to %s", req.StartDate, req.EndDate)
+
+	if req.GroupBy != nil && *req.GroupBy != model.GroupByServiceName && *req.GroupBy != model.GroupByUserID {
+		return nil, model.NewValidationError("group_by", "must be service_name or user_id")
+	}
+
+	startPeriod, endPeriod, err := parsePeriod(req.TotalCostRequest)
+	if err != nil {
+		return nil, err
+	}
+	if monthKey(endPeriod)-monthKey(startPeriod)+1 > maxBreakdownMonths {
+		return nil, model.NewValidationError("end_date", fmt.Sprintf("period must not be longer than %d months", maxBreakdownMonths))
+	}
+
+	// Все месяцы считаются по одной выборке, поэтому ряд согласован даже при параллельных изменениях
+	subscriptions, err := s.repo.ListByPeriod(startPeriod, lastDayOfMonth(endPeriod), req.UserID, req.ServiceName)
+	if err != nil {
+		log.Printf("Error calculating cost breakdown: %v", err)
+		return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+	}
+
+	currency := reportCurrency(req.Currency)
+	rates, err := s.loadRates(currency, subscriptions, endPeriod)
+	if err != nil {
+		log.Printf("Error calculating cost breakdown: %v", err)
+		return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+	}
+
+	// Списания считаются один раз на весь период и заранее распределяются по месяцам своих дат
+	byMonth := make(map[int][]monthCharge)
+	for i, sub := range subscriptions {
+		charges, err := subscriptionCharges(sub, startPeriod, lastDayOfMonth(endPeriod), req.Proration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+		}
+		for _, c := range charges {
+			key := monthKey(c.date)
+			byMonth[key] = append(byMonth[key], monthCharge{sub: i, amount: c.amount})
+		}
+	}
+
+	result := &model.CostBreakdownResponse{Months: []model.MonthlyCost{}, Currency: currency}
+	var total costSum
+	for month := startPeriod; !month.After(endPeriod); month = month.AddDate(0, 1, 0) {
+		monthly := model.MonthlyCost{Month: month.Format("2006-01")}
+		groups := make(map[string]*costSum)
+		var monthTotal costSum
+
+		for _, c := range byMonth[monthKey(month)] {
+			sub := subscriptions[c.sub]
+			amount, err := rates.convert(model.Money{Amount: c.amount, Currency: sub.Currency}, month)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+			}
+
+			monthTotal.add(amount)
+			if req.GroupBy != nil {
+				key := groupKey(sub, *req.GroupBy)
+				if groups[key] == nil {
+					groups[key] = &costSum{}
+				}
+				groups[key].add(amount)
+			}
+		}
+
+		if monthly.TotalCost, err = monthTotal.result(); err != nil {
+			return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+		}
+
+		if req.GroupBy != nil {
+			if monthly.Groups, err = sortedGroups(groups); err != nil {
+				return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+			}
+		}
+
+		result.Months = append(result.Months, monthly)
+		total.add(monthly.TotalCost)
+	}
+
+	if result.TotalCost, err = total.result(); err != nil {
+		return nil, fmt.Errorf("failed to calculate cost breakdown: %w", err)
+	}
+
+	log.Printf("Cost breakdown calculated for %d months", len(result.Months))
+	return result, nil
+}
+
+// groupKey возвращает значение поля подписки, по которому выполняется группировка
+func groupKey(sub model.Subscription, groupBy string) string {
+	if groupBy == model.GroupByUserID {
+		return sub.UserID.String()
+	}
+
+	return sub.ServiceName
+}
+
+// sortedGroups превращает накопленные суммы в список, упорядоченный по ключу
+func sortedGroups(groups map[string]*costSum) ([]model.GroupCost, error) {
+	result := make([]model.GroupCost, 0, len(groups))
+	for key, sum := range groups {
+		total, err := sum.result()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, model.GroupCost{Key: key, TotalCost: total})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+
+	return result, nil
+}
+
+func (s *subscriptionService) SpendReport(req model.SpendReportRequest) (*model.SpendReportResponse, error) {
+	log.Printf("Calculating spend report for period %s to %s grouped by %v", req.StartDate, req.EndDate, req.GroupBy)
+
+	groupByService, groupByUser, err := parseGroupBy(req.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	sortBy := model.SortByTotalCost
+	if req.SortBy != nil {
+		sortBy = *req.SortBy
+	}
+	if sortBy != model.SortByTotalCost && sortBy != model.SortByKey {
+		return nil, model.NewValidationError("sort_by", "must be total_cost or key")
+	}
+
+	order := model.SortOrderDesc
+	if req.Order != nil {
+		order = *req.Order
+	}
+	if order != model.SortOrderAsc && order != model.SortOrderDesc {
+		return nil, model.NewValidationError("order", "must be asc or desc")
+	}
+
+	if req.Limit != nil && *req.Limit <= 0 {
+		return nil, model.NewValidationError("limit", "must be positive")
+	}
+
+	startPeriod, endPeriod, err := parsePeriod(req.TotalCostRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.repo.ListByPeriod(startPeriod, lastDayOfMonth(endPeriod), req.UserID, req.ServiceName)
+	if err != nil {
+		log.Printf("Error calculating spend report: %v", err)
+		return nil, fmt.Errorf("failed to calculate spend report: %w", err)
+	}
+
+	currency := reportCurrency(req.Currency)
+	rates, err := s.loadRates(currency, subscriptions, endPeriod)
+	if err != nil {
+		log.Printf("Error calculating spend report: %v", err)
+		return nil, fmt.Errorf("failed to calculate spend report: %w", err)
+	}
+
+	type groupID struct {
+		serviceName string
+		userID      string
+	}
+
+	groups := make(map[groupID]*model.SpendGroup)
+	totals := make(map[groupID]*costSum)
+	result := &model.SpendReportResponse{Groups: []model.SpendGroup{}, Currency: currency}
+
+	var total costSum
+	for _, sub := range subscriptions {
+		months := billedMonths(sub, startPeriod, endPeriod)
+		if months == 0 {
+			continue
+		}
+
+		cost, err := rates.billedCost(sub, startPeriod, endPeriod, req.Proration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate spend report: %w", err)
+		}
+
+		var id groupID
+		if groupByService {
+			id.serviceName = sub.ServiceName
+		}
+		if groupByUser {
+			id.userID = sub.UserID.String()
+		}
+
+		group, ok := groups[id]
+		if !ok {
+			group = &model.SpendGroup{}
+			if groupByService {
+				serviceName := sub.ServiceName
+				group.ServiceName = &serviceName
+			}
+			if groupByUser {
+				userID := sub.UserID
+				group.UserID = &userID
+			}
+			groups[id] = group
+			totals[id] = &costSum{}
+		}
+
+		totals[id].add(cost)
+		group.Months += months
+		total.add(cost)
+	}
+
+	if result.TotalCost, err = total.result(); err != nil {
+		return nil, fmt.Errorf("failed to calculate spend report: %w", err)
+	}
+
+	for id, group := range groups {
+		if group.TotalCost, err = totals[id].result(); err != nil {
+			return nil, fmt.Errorf("failed to calculate spend report: %w", err)
+		}
+		result.Groups = append(result.Groups, *group)
+	}
+
+	sort.Slice(result.Groups, func(i, j int) bool {
+		a, b := result.Groups[i], result.Groups[j]
+		if order == model.SortOrderDesc {
+			a, b = b, a
+		}
+
+		if sortBy == model.SortByTotalCost && a.TotalCost != b.TotalCost {
+			return a.TotalCost < b.TotalCost
+		}
+
+		return spendGroupKey(a) < spendGroupKey(b)
+	})
+
+	if req.Limit != nil && int(*req.Limit) < len(result.Groups) {
+		result.Groups = result.Groups[:*req.Limit]
+	}
+
+	log.Printf("Spend report calculated: %d groups, total %s", len(result.Groups), result.TotalCost)
+	return result, nil
+}
+
+// parseGroupBy проверяет поля группировки отчета о расходах
+func parseGroupBy(groupBy []string) (bool, bool, error) {
+	if len(groupBy) == 0 {
+		return false, false, model.NewValidationError("group_by", "must contain at least one field")
+	}
+
+	var byService, byUser bool
+	for _, field := range groupBy {
+		switch field {
+		case model.GroupByServiceName:
+			byService = true
+		case model.GroupByUserID:
+			byUser = true
+		default:
+			return false, false, model.NewValidationError("group_by", "must contain only service_name and user_id")
+		}
+	}
+
+	return byService, byUser, nil
+}
+
+// spendGroupKey возвращает ключ группы для сортировки и разрешения равенства сумм
+func spendGroupKey(group model.SpendGroup) string {
+	var key string
+	if group.ServiceName != nil {
+		key = *group.ServiceName
+	}
+	if group.UserID != nil {
+		key += "/" + group.UserID.String()
+	}
+
+	return key
+}
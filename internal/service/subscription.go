@@ -3,163 +3,582 @@ package service
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Fedasov/Effective-Mobile/internal/model"
 	"github.com/Fedasov/Effective-Mobile/internal/repository"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+	"github.com/google/uuid"
 )
 
+// Options задает настраиваемое поведение сервиса подписок
+type Options struct {
+	// IdempotencyTTL - время, в течение которого повтор запроса с тем же ключом возвращает исходный ответ
+	IdempotencyTTL time.Duration
+	// OverlapPolicy - поведение при создании или изменении подписки, пересекающейся с существующей
+	OverlapPolicy model.OverlapPolicy
+	// DeletedRetention - срок, после которого мягко удаленные подписки удаляются окончательно
+	DeletedRetention time.Duration
+}
+
 type subscriptionService struct {
-	repo repository.SubscriptionRepository
+	repo  repository.SubscriptionRepository
+	rates repository.RateRepository
+	opts  Options
 }
 
-func NewSubscriptionService(repo repository.SubscriptionRepository) *subscriptionService {
-	return &subscriptionService{repo: repo}
+func NewSubscriptionService(repo repository.SubscriptionRepository, rates repository.RateRepository, opts Options) *subscriptionService {
+	return &subscriptionService{repo: repo, rates: rates, opts: opts}
 }
 
-func (s *subscriptionService) Create(req model.SubscriptionCreateRequest) (*model.Subscription, error) {
+func (s *subscriptionService) Create(req model.SubscriptionCreateRequest, meta model.AuditMeta) (*model.Subscription, error) {
 	log.Printf("Creating subscription for user %s to service %s", req.UserID, req.ServiceName)
 
+	subscription, err := newSubscription(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Сохранение в репозитории
+	if err := s.repo.Create(subscription, s.opts.OverlapPolicy, meta); err != nil {
+		log.Printf("Error creating subscription: %v", err)
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	log.Printf("Subscription created successfully with ID: %d", subscription.ID)
+	return subscription, nil
+}
+
+// CreateIdempotent создает подписку один раз для ключа идемпотентности. Повтор с тем же ключом
+// в пределах IdempotencyTTL возвращает сохраненную подписку и replayed = true
+func (s *subscriptionService) CreateIdempotent(req model.SubscriptionCreateRequest, key string, meta model.AuditMeta) (*model.Subscription, bool, error) {
+	log.Printf("Creating subscription for user %s to service %s with idempotency key %q", req.UserID, req.ServiceName, key)
+
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, false, model.NewValidationError("Idempotency-Key", fmt.Sprintf("must be at most %d characters long", maxIdempotencyKeyLength))
+	}
+
+	subscription, err := newSubscription(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	requestHash, err := hashRequest(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	idempotencyKey := model.IdempotencyKey{Key: key, RequestHash: requestHash, TTL: s.opts.IdempotencyTTL}
+
+	replayed, err := s.repo.CreateIdempotent(subscription, idempotencyKey, s.opts.OverlapPolicy, meta)
+	if err != nil {
+		log.Printf("Error creating subscription: %v", err)
+		return nil, false, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	if replayed {
+		log.Printf("Replayed subscription %d for idempotency key %q", subscription.ID, key)
+	} else {
+		log.Printf("Subscription created successfully with ID: %d", subscription.ID)
+	}
+
+	return subscription, replayed, nil
+}
+
+func (s *subscriptionService) PurgeIdempotencyKeys() error {
+	deleted, err := s.repo.PurgeIdempotencyKeys(time.Now().Add(-s.opts.IdempotencyTTL))
+	if err != nil {
+		log.Printf("Error purging idempotency keys: %v", err)
+		return fmt.Errorf("failed to purge idempotency keys: %w", err)
+	}
+
+	if deleted > 0 {
+		log.Printf("Purged %d expired idempotency keys", deleted)
+	}
+	return nil
+}
+
+// newSubscription проверяет запрос и строит по нему модель подписки
+func newSubscription(req model.SubscriptionCreateRequest) (*model.Subscription, error) {
+	if err := validateSubscriptionRequest(req); err != nil {
+		return nil, err
+	}
+
 	// Преобразование дат из строкового формата
-	startDate, err := parseMonthYear(req.StartDate)
+	startDate, err := parseStartDate(req.StartDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start date: %v", err)
+		return nil, model.NewValidationError("start_date", dateFormatMessage)
 	}
 
 	var endDate *time.Time
 	if req.EndDate != nil {
-		parsedEndDate, err := parseMonthYear(*req.EndDate)
+		parsedEndDate, err := parseEndDate(*req.EndDate)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end date: %v", err)
+			return nil, model.NewValidationError("end_date", dateFormatMessage)
 		}
 		endDate = &parsedEndDate
 	}
 
-	// Создание модели подписки
-	subscription := &model.Subscription{
-		ServiceName: req.ServiceName,
-		Price:       req.Price,
-		UserID:      req.UserID,
-		StartDate:   startDate,
-		EndDate:     endDate,
+	billingPeriod, billingInterval, anchorDay := requestBilling(req, startDate)
+
+	return &model.Subscription{
+		ServiceID:       serviceID(req),
+		ServiceName:     req.ServiceName,
+		Price:           req.Price,
+		Currency:        requestCurrency(req),
+		BillingPeriod:   billingPeriod,
+		BillingInterval: billingInterval,
+		AnchorDay:       anchorDay,
+		TrialDays:       req.TrialDays,
+		UserID:          req.UserID,
+		StartDate:       startDate,
+		EndDate:         endDate,
+	}, nil
+}
+
+// requestCurrency возвращает код валюты из запроса или пустую строку, если валюту нужно взять из каталога
+func requestCurrency(req model.SubscriptionCreateRequest) string {
+	if req.Currency == nil {
+		return ""
 	}
 
-	// Сохранение в репозитории
-	if err := s.repo.Create(subscription); err != nil {
-		log.Printf("Error creating subscription: %v", err)
-		return nil, fmt.Errorf("failed to create subscription: %v", err)
+	return strings.ToUpper(*req.Currency)
+}
+
+// serviceID возвращает ID сервиса из запроса или 0, если сервис задан названием
+func serviceID(req model.SubscriptionCreateRequest) uint32 {
+	if req.ServiceID == nil {
+		return 0
 	}
 
-	log.Printf("Subscription created successfully with ID: %d", subscription.ID)
-	return subscription, nil
+	return *req.ServiceID
 }
 
-func (s *subscriptionService) GetByID(id uint32) (*model.Subscription, error) {
+func (s *subscriptionService) GetByID(id uint32, includeDeleted bool) (*model.Subscription, error) {
 	log.Printf("Getting subscription with ID: %d", id)
 
-	subscription, err := s.repo.GetByID(id)
+	subscription, err := s.repo.GetByID(id, includeDeleted)
 	if err != nil {
 		log.Printf("Error getting subscription %d: %v", id, err)
-		return nil, fmt.Errorf("failed to get subscription: %v", err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
 	return subscription, nil
 }
 
-func (s *subscriptionService) Update(id uint32, req model.SubscriptionCreateRequest) (*model.Subscription, error) {
+func (s *subscriptionService) Update(id uint32, req model.SubscriptionCreateRequest, expectedVersion *uint32, meta model.AuditMeta) (*model.Subscription, error) {
 	log.Printf("Updating subscription with ID: %d", id)
 
-	existing, err := s.repo.GetByID(id)
+	if err := validateSubscriptionRequest(req); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(id, false)
 	if err != nil {
-		return nil, fmt.Errorf("subscription not found: %v", err)
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
 	}
 
-	startDate, err := parseMonthYear(req.StartDate)
+	startDate, err := parseStartDate(req.StartDate)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start date: %v", err)
+		return nil, model.NewValidationError("start_date", dateFormatMessage)
 	}
 
 	var endDate *time.Time
 	if req.EndDate != nil {
-		parsedEndDate, err := parseMonthYear(*req.EndDate)
+		parsedEndDate, err := parseEndDate(*req.EndDate)
 		if err != nil {
-			return nil, fmt.Errorf("invalid end date: %v", err)
+			return nil, model.NewValidationError("end_date", dateFormatMessage)
 		}
 		endDate = &parsedEndDate
 	}
 
+	existing.ServiceID = serviceID(req)
 	existing.ServiceName = req.ServiceName
 	existing.Price = req.Price
+	existing.Currency = requestCurrency(req)
+	existing.BillingPeriod, existing.BillingInterval, existing.AnchorDay = requestBilling(req, startDate)
+	existing.TrialDays = req.TrialDays
 	existing.UserID = req.UserID
 	existing.StartDate = startDate
 	existing.EndDate = endDate
 
-	if err := s.repo.Update(existing); err != nil {
+	if err := s.repo.Update(existing, expectedVersion, s.opts.OverlapPolicy, meta); err != nil {
 		log.Printf("Error updating subscription %d: %v", id, err)
-		return nil, fmt.Errorf("failed to update subscription: %v", err)
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
 	}
 
 	log.Printf("Subscription %d updated successfully", id)
 	return existing, nil
 }
 
-func (s *subscriptionService) Delete(id uint32) error {
+func (s *subscriptionService) Patch(id uint32, req model.SubscriptionPatchRequest, expectedVersion *uint32, meta model.AuditMeta) (*model.Subscription, error) {
+	log.Printf("Patching subscription with ID: %d", id)
+
+	existing, err := s.repo.GetByID(id, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	merged, err := mergePatch(*existing, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSubscriptionRequest(merged); err != nil {
+		return nil, err
+	}
+
+	patch, err := diffPatch(*existing, merged)
+	if err != nil {
+		return nil, err
+	}
+
+	// Изменений нет: запись не трогаем, но условие версии все равно проверяем
+	if patch.IsEmpty() {
+		if expectedVersion != nil && *expectedVersion != existing.Version {
+			return nil, fmt.Errorf("subscription with ID %d was modified: %w", id, model.ErrPreconditionFailed)
+		}
+		return existing, nil
+	}
+
+	updated, err := s.repo.Patch(id, patch, expectedVersion, s.opts.OverlapPolicy, meta)
+	if err != nil {
+		log.Printf("Error patching subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to patch subscription: %w", err)
+	}
+
+	log.Printf("Subscription %d patched successfully", id)
+	return updated, nil
+}
+
+func (s *subscriptionService) Delete(id uint32, expectedVersion *uint32, meta model.AuditMeta) error {
 	log.Printf("Deleting subscription with ID: %d", id)
 
-	if err := s.repo.Delete(id); err != nil {
+	if err := s.repo.Delete(id, expectedVersion, meta); err != nil {
 		log.Printf("Error deleting subscription %d: %v", id, err)
-		return fmt.Errorf("failed to delete subscription: %v", err)
+		return fmt.Errorf("failed to delete subscription: %w", err)
 	}
 
 	log.Printf("Subscription %d deleted successfully", id)
 	return nil
 }
 
-func (s *subscriptionService) List(limit, offset int32) ([]model.Subscription, error) {
-	log.Printf("Getting subscriptions list with limit: %d, offset: %d", limit, offset)
+func (s *subscriptionService) Restore(id uint32, meta model.AuditMeta) (*model.Subscription, error) {
+	log.Printf("Restoring subscription with ID: %d", id)
 
-	subscriptions, err := s.repo.List(limit, offset)
+	subscription, err := s.repo.Restore(id, s.opts.OverlapPolicy, meta)
+	if err != nil {
+		log.Printf("Error restoring subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to restore subscription: %w", err)
+	}
+
+	log.Printf("Subscription %d restored successfully", id)
+	return subscription, nil
+}
+
+// History возвращает журнал изменений подписки, в том числе удаленной
+func (s *subscriptionService) History(id uint32) ([]model.AuditEntry, error) {
+	log.Printf("Getting history of subscription with ID: %d", id)
+
+	entries, err := s.repo.ListAudit(id)
+	if err != nil {
+		log.Printf("Error getting history of subscription %d: %v", id, err)
+		return nil, fmt.Errorf("failed to get subscription history: %w", err)
+	}
+
+	// Пустой журнал у существующей подписки возможен для записей, созданных до его появления
+	if len(entries) == 0 {
+		if _, err := s.repo.GetByID(id, true); err != nil {
+			return nil, fmt.Errorf("failed to get subscription: %w", err)
+		}
+	}
+
+	return entries, nil
+}
+
+func (s *subscriptionService) PurgeDeleted() error {
+	deleted, err := s.repo.PurgeDeleted(time.Now().Add(-s.opts.DeletedRetention))
+	if err != nil {
+		log.Printf("Error purging deleted subscriptions: %v", err)
+		return fmt.Errorf("failed to purge deleted subscriptions: %w", err)
+	}
+
+	if deleted > 0 {
+		log.Printf("Purged %d deleted subscriptions", deleted)
+	}
+	return nil
+}
+
+func (s *subscriptionService) List(req model.SubscriptionListRequest) (*model.SubscriptionPage, error) {
+	log.Printf("Getting subscriptions list with limit: %d, offset: %d", req.Limit, req.Offset)
+
+	filter, err := parseListFilter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.SubscriptionPage{}
+
+	if req.WithTotal {
+		total, err := s.repo.Count(*filter)
+		if err != nil {
+			log.Printf("Error counting subscriptions: %v", err)
+			return nil, fmt.Errorf("failed to count subscriptions: %w", err)
+		}
+		page.Total = &total
+	}
+
+	// В режиме курсора запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	if req.UseCursor {
+		filter.Limit++
+	}
+
+	subscriptions, err := s.repo.List(*filter)
 	if err != nil {
 		log.Printf("Error getting subscriptions list: %v", err)
-		return nil, fmt.Errorf("failed to get subscriptions list: %v", err)
+		return nil, fmt.Errorf("failed to get subscriptions list: %w", err)
+	}
+
+	if req.UseCursor && int32(len(subscriptions)) == filter.Limit {
+		subscriptions = subscriptions[:len(subscriptions)-1]
+
+		next, err := encodeCursor(subscriptions[len(subscriptions)-1], filter.SortBy, filter.SortDesc)
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = &next
+	}
+
+	if subscriptions == nil {
+		subscriptions = []model.Subscription{}
 	}
+	page.Items = subscriptions
 
 	log.Printf("Retrieved %d subscriptions", len(subscriptions))
-	return subscriptions, nil
+	return page, nil
 }
 
-func (s *subscriptionService) CalculateTotalCost(req model.TotalCostRequest) (int32, error) {
-	log.Printf("Calculating total cost for period %s to %s", req.StartDate, req.EndDate)
+func (s *subscriptionService) ListByUser(userID uuid.UUID) (*model.UserSubscriptionsResponse, error) {
+	log.Printf("Getting subscriptions for user %s", userID)
 
-	startPeriod, err := parseMonthYear(req.StartDate)
+	subscriptions, err := s.repo.ListByUser(userID)
 	if err != nil {
-		return 0, fmt.Errorf("invalid start period: %v", err)
+		log.Printf("Error getting subscriptions for user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to get user subscriptions: %w", err)
 	}
 
-	endPeriod, err := parseMonthYear(req.EndDate)
+	month := currentMonth()
+	now := today()
+
+	rates, err := s.loadRates(model.DefaultCurrency, subscriptions, month)
 	if err != nil {
-		return 0, fmt.Errorf("invalid end period: %v", err)
+		log.Printf("Error getting subscriptions for user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to get user subscriptions: %w", err)
+	}
+
+	result := &model.UserSubscriptionsResponse{
+		UserID:        userID,
+		Subscriptions: make([]model.UserSubscription, 0, len(subscriptions)),
+		Currency:      model.DefaultCurrency,
 	}
+	var monthlySpend costSum
+	for _, sub := range subscriptions {
+		status := subscriptionStatus(sub, now)
+		if status == model.StatusActive {
+			price, err := monthlyEquivalent(sub, chargePrice(sub, now, now))
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate monthly spend: %w", err)
+			}
+
+			amount, err := rates.convert(model.Money{Amount: price, Currency: sub.Currency}, month)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate monthly spend: %w", err)
+			}
+			monthlySpend.add(amount)
+		}
 
-	endPeriod = time.Date(endPeriod.Year(), endPeriod.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+		result.Subscriptions = append(result.Subscriptions, model.UserSubscription{Subscription: sub, Status: status})
+	}
 
-	total, err := s.repo.CalculateTotalCost(startPeriod, endPeriod, req.UserID, req.ServiceName)
+	if result.MonthlySpend, err = monthlySpend.result(); err != nil {
+		return nil, fmt.Errorf("failed to calculate monthly spend: %w", err)
+	}
+
+	log.Printf("Retrieved %d subscriptions for user %s", len(subscriptions), userID)
+	return result, nil
+}
+
+func (s *subscriptionService) CalculateTotalCost(req model.TotalCostRequest) (*model.TotalCostResponse, error) {
+	log.Printf("Calculating total cost for period %s to %s", req.StartDate, req.EndDate)
+
+	startPeriod, endPeriod, err := parsePeriod(req)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.repo.ListByPeriod(startPeriod, lastDayOfMonth(endPeriod), req.UserID, req.ServiceName)
+	if err != nil {
+		log.Printf("Error calculating total cost: %v", err)
+		return nil, fmt.Errorf("failed to calculate total cost: %w", err)
+	}
+
+	currency := reportCurrency(req.Currency)
+	rates, err := s.loadRates(currency, subscriptions, endPeriod)
 	if err != nil {
 		log.Printf("Error calculating total cost: %v", err)
-		return 0, fmt.Errorf("failed to calculate total cost: %v", err)
+		return nil, fmt.Errorf("failed to calculate total cost: %w", err)
+	}
+
+	result := &model.TotalCostResponse{Currency: currency, ByCurrency: []model.CurrencyTotal{}}
+	byCurrency := make(map[string]*model.CurrencyTotal)
+	rawTotals := make(map[string]*costSum)
+	var total costSum
+	for _, sub := range subscriptions {
+		cost, months, err := billedCost(sub, startPeriod, endPeriod, req.Proration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate total cost: %w", err)
+		}
+		if months == 0 {
+			continue
+		}
+
+		converted, err := rates.billedCost(sub, startPeriod, endPeriod, req.Proration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate total cost: %w", err)
+		}
+		total.add(converted)
+		result.Months += months
+
+		raw, ok := byCurrency[sub.Currency]
+		if !ok {
+			raw = &model.CurrencyTotal{Currency: sub.Currency}
+			byCurrency[sub.Currency] = raw
+			rawTotals[sub.Currency] = &costSum{}
+		}
+		rawTotals[sub.Currency].add(cost)
+		raw.Months += months
+	}
+
+	if result.TotalCost, err = total.result(); err != nil {
+		return nil, fmt.Errorf("failed to calculate total cost: %w", err)
 	}
 
-	log.Printf("Total cost calculated: %d", total)
-	return total, nil
+	for currency, raw := range byCurrency {
+		if raw.TotalCost, err = rawTotals[currency].result(); err != nil {
+			return nil, fmt.Errorf("failed to calculate total cost: %w", err)
+		}
+		result.ByCurrency = append(result.ByCurrency, *raw)
+	}
+	sort.Slice(result.ByCurrency, func(i, j int) bool {
+		return result.ByCurrency[i].Currency < result.ByCurrency[j].Currency
+	})
+
+	log.Printf("Total cost calculated: %s %s for %d months", result.TotalCost, result.Currency, result.Months)
+	return result, nil
+}
+
+// billedMonths возвращает количество месяцев подписки, попадающих в период [from, to].
+// Обе границы периода и даты подписки считаются включительно.
+func billedMonths(sub model.Subscription, from, to time.Time) int32 {
+	start := sub.StartDate
+	if start.Before(from) {
+		start = from
+	}
+
+	end := to
+	if sub.EndDate != nil && sub.EndDate.Before(to) {
+		end = *sub.EndDate
+	}
+
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month()) + 1
+	if months < 0 {
+		return 0
+	}
+
+	return int32(months)
+}
+
+// subscriptionStatus определяет статус подписки относительно указанного дня
+func subscriptionStatus(sub model.Subscription, day time.Time) string {
+	if sub.StartDate.After(day) {
+		return model.StatusUpcoming
+	}
+
+	if sub.EndDate != nil && sub.EndDate.Before(day) {
+		return model.StatusExpired
+	}
+
+	return model.StatusActive
 }
 
+// parsePeriod разбирает границы периода отчета и возвращает первые дни начального и конечного месяцев
+func parsePeriod(req model.TotalCostRequest) (time.Time, time.Time, error) {
+	if err := validatePeriodRequest(req); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	startPeriod, err := parseMonthYear(req.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, model.NewValidationError("start_date", monthFormatMessage)
+	}
+
+	endPeriod, err := parseMonthYear(req.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, model.NewValidationError("end_date", monthFormatMessage)
+	}
+
+	return startPeriod, endPeriod, nil
+}
+
+// lastDayOfMonth возвращает последний день месяца, которому принадлежит дата
+func lastDayOfMonth(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// monthLayout - формат месяца "MM-YYYY", в котором API принимает даты
+const monthLayout = validator.MonthLayout
+
+// monthFormatMessage описывает ожидаемый формат месяца в ошибках валидации
+const monthFormatMessage = "must be a month in MM-YYYY format"
+
 // parseMonthYear преобразует строку формата "MM-YYYY" в time.Time
 func parseMonthYear(monthYear string) (time.Time, error) {
-	layout := "01-2006"
-	date, err := time.Parse(layout, monthYear)
+	date, err := time.Parse(monthLayout, monthYear)
 	if err != nil {
 		return time.Time{}, err
 	}
 
 	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC), nil
 }
+
+// dateLayout - формат полной даты "YYYY-MM-DD", в котором API принимает и возвращает даты подписок
+const dateLayout = validator.DateLayout
+
+// dateFormatMessage описывает ожидаемый формат даты подписки в ошибках валидации
+const dateFormatMessage = "must be a date in YYYY-MM-DD or MM-YYYY format"
+
+// parseStartDate разбирает дату начала подписки; месяц без дня означает его первое число
+func parseStartDate(value string) (time.Time, error) {
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return date, nil
+	}
+
+	return parseMonthYear(value)
+}
+
+// parseEndDate разбирает дату окончания подписки; месяц без дня означает его последнее число,
+// чтобы подписка, как и раньше, действовала весь месяц окончания
+func parseEndDate(value string) (time.Time, error) {
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return date, nil
+	}
+
+	month, err := parseMonthYear(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return lastDayOfMonth(month), nil
+}
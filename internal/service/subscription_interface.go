@@ -1,14 +1,37 @@
 package service
 
 import (
+	"io"
+
 	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/google/uuid"
 )
 
 type SubscriptionService interface {
-	Create(req model.SubscriptionCreateRequest) (*model.Subscription, error)
-	GetByID(id uint32) (*model.Subscription, error)
-	Update(id uint32, req model.SubscriptionCreateRequest) (*model.Subscription, error)
-	Delete(id uint32) error
-	List(limit, offset int32) ([]model.Subscription, error)
-	CalculateTotalCost(req model.TotalCostRequest) (int32, error)
+	Create(req model.SubscriptionCreateRequest, meta model.AuditMeta) (*model.Subscription, error)
+	CreateIdempotent(req model.SubscriptionCreateRequest, key string, meta model.AuditMeta) (*model.Subscription, bool, error)
+	PurgeIdempotencyKeys() error
+	BulkCreate(req model.BulkCreateRequest, meta model.AuditMeta) (*model.BulkResult, error)
+	BulkUpdate(req model.BulkUpdateRequest, meta model.AuditMeta) (*model.BulkResult, error)
+	BulkDelete(req model.BulkDeleteRequest, meta model.AuditMeta) (*model.BulkResult, error)
+	ImportCSV(src io.Reader, mode model.BulkMode, dryRun bool, meta model.AuditMeta) (*model.ImportResult, error)
+	GetByID(id uint32, includeDeleted bool) (*model.Subscription, error)
+	Update(id uint32, req model.SubscriptionCreateRequest, expectedVersion *uint32, meta model.AuditMeta) (*model.Subscription, error)
+	Patch(id uint32, req model.SubscriptionPatchRequest, expectedVersion *uint32, meta model.AuditMeta) (*model.Subscription, error)
+	Delete(id uint32, expectedVersion *uint32, meta model.AuditMeta) error
+	Restore(id uint32, meta model.AuditMeta) (*model.Subscription, error)
+	PurgeDeleted() error
+	History(id uint32) ([]model.AuditEntry, error)
+	PriceSchedule(id uint32) ([]model.PriceChange, error)
+	SchedulePriceChange(id uint32, req model.PriceChangeRequest, expectedVersion *uint32, meta model.AuditMeta) ([]model.PriceChange, error)
+	CancelPriceChange(id uint32, month string, expectedVersion *uint32, meta model.AuditMeta) error
+	Promotions(id uint32) ([]model.Promotion, error)
+	AddPromotion(id uint32, req model.PromotionRequest, expectedVersion *uint32, meta model.AuditMeta) (*model.Promotion, error)
+	DeletePromotion(id uint32, promotionID uint32, expectedVersion *uint32, meta model.AuditMeta) error
+	List(req model.SubscriptionListRequest) (*model.SubscriptionPage, error)
+	Export(req model.SubscriptionListRequest, fn func(*model.Subscription) error) error
+	ListByUser(userID uuid.UUID) (*model.UserSubscriptionsResponse, error)
+	CalculateTotalCost(req model.TotalCostRequest) (*model.TotalCostResponse, error)
+	CostBreakdown(req model.CostBreakdownRequest) (*model.CostBreakdownResponse, error)
+	SpendReport(req model.SpendReportRequest) (*model.SpendReportResponse, error)
 }
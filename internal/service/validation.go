@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/Fedasov/Effective-Mobile/internal/validator"
+)
+
+// validateSubscriptionRequest проверяет теги validate и бизнес-правила запроса подписки,
+// возвращая ошибки всех полей сразу
+func validateSubscriptionRequest(req model.SubscriptionCreateRequest) error {
+	req.Currency = upperCurrency(req.Currency)
+	fields := validator.Struct(req)
+
+	switch {
+	case req.ServiceID != nil:
+		if *req.ServiceID == 0 {
+			fields = append(fields, model.FieldError{Field: "service_id", Message: "must be positive"})
+		}
+	case req.ServiceName == "":
+		fields = append(fields, model.FieldError{Field: "service_name", Message: "is required unless service_id is set"})
+	case strings.TrimSpace(req.ServiceName) == "":
+		fields = append(fields, model.FieldError{Field: "service_name", Message: "must not be blank"})
+	}
+
+	if !hasFieldError(fields, "billing_period") {
+		fields = append(fields, checkBilling(req)...)
+	}
+
+	if req.EndDate != nil && !hasFieldError(fields, "start_date") && !hasFieldError(fields, "end_date") {
+		fields = append(fields, checkDateOrder(req.StartDate, *req.EndDate)...)
+	}
+
+	return newValidationError(fields)
+}
+
+// validatePeriodRequest проверяет параметры периода отчета
+func validatePeriodRequest(req model.TotalCostRequest) error {
+	req.Currency = upperCurrency(req.Currency)
+	fields := validator.Struct(req)
+
+	if !hasFieldError(fields, "start_date") && !hasFieldError(fields, "end_date") {
+		fields = append(fields, checkMonthOrder(req.StartDate, req.EndDate)...)
+	}
+
+	return newValidationError(fields)
+}
+
+// upperCurrency приводит код валюты к верхнему регистру, в котором его проверяет правило iso4217
+func upperCurrency(currency *string) *string {
+	if currency == nil {
+		return nil
+	}
+
+	upper := strings.ToUpper(*currency)
+	return &upper
+}
+
+// checkMonthOrder проверяет, что месяц окончания не раньше месяца начала.
+// Оба значения должны быть уже проверены на формат
+func checkMonthOrder(start, end string) []model.FieldError {
+	startDate, _ := parseMonthYear(start)
+	endDate, _ := parseMonthYear(end)
+
+	if endDate.Before(startDate) {
+		return []model.FieldError{{Field: "end_date", Message: "must not be before start_date"}}
+	}
+
+	return nil
+}
+
+// checkDateOrder проверяет, что дата окончания подписки не раньше даты начала.
+// Оба значения должны быть уже проверены на формат
+func checkDateOrder(start, end string) []model.FieldError {
+	startDate, _ := parseStartDate(start)
+	endDate, _ := parseEndDate(end)
+
+	if endDate.Before(startDate) {
+		return []model.FieldError{{Field: "end_date", Message: "must not be before start_date"}}
+	}
+
+	return nil
+}
+
+// checkBilling проверяет, что интервал и день списания подходят к периодичности
+func checkBilling(req model.SubscriptionCreateRequest) []model.FieldError {
+	var fields []model.FieldError
+	period := billingPeriod(req)
+
+	interval, fixed := model.BillingIntervals[period]
+	switch {
+	case !fixed && req.BillingInterval == nil:
+		fields = append(fields, model.FieldError{Field: "billing_interval", Message: "is required for custom billing_period"})
+	case fixed && req.BillingInterval != nil && *req.BillingInterval != interval:
+		fields = append(fields, model.FieldError{Field: "billing_interval", Message: fmt.Sprintf("must be %d for %s billing_period", interval, period)})
+	}
+
+	if period == model.BillingWeekly && req.AnchorDay != nil && *req.AnchorDay > 7 {
+		fields = append(fields, model.FieldError{Field: "anchor_day", Message: "must be a day of week from 1 to 7 for weekly billing_period"})
+	}
+
+	return fields
+}
+
+func hasFieldError(fields []model.FieldError, field string) bool {
+	for _, f := range fields {
+		if f.Field == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newValidationError возвращает ошибку валидации, если найдены ошибки полей
+func newValidationError(fields []model.FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &model.ValidationError{Fields: fields}
+}
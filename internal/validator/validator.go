@@ -0,0 +1,132 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Fedasov/Effective-Mobile/internal/model"
+	"github.com/go-playground/validator/v10"
+)
+
+// MonthLayout - формат месяца "MM-YYYY", в котором API принимает даты
+const MonthLayout = "01-2006"
+
+// DateLayout - формат полной даты "YYYY-MM-DD" (ISO 8601)
+const DateLayout = "2006-01-02"
+
+var validate = newValidate()
+
+func newValidate() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// В ошибках используем имена полей из JSON, чтобы клиент мог сопоставить их с запросом
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	v.RegisterValidation("month", func(fl validator.FieldLevel) bool {
+		_, err := time.Parse(MonthLayout, fl.Field().String())
+		return err == nil
+	})
+
+	// Даты подписки принимаются с точностью до дня или, как раньше, до месяца
+	v.RegisterValidation("date", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if _, err := time.Parse(DateLayout, value); err == nil {
+			return true
+		}
+		_, err := time.Parse(MonthLayout, value)
+		return err == nil
+	})
+
+	return v
+}
+
+// Struct проверяет структуру по тегам validate и возвращает ошибки всех полей сразу
+func Struct(s interface{}) []model.FieldError {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return []model.FieldError{{Field: "", Message: err.Error()}}
+	}
+
+	fields := make([]model.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fields = append(fields, model.FieldError{Field: fe.Field(), Message: message(fe)})
+	}
+
+	return fields
+}
+
+// message формирует понятное клиенту описание нарушенного правила
+func message(fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+	param := messageParam(fe)
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "month":
+		return "must be a month in MM-YYYY format"
+	case "date":
+		return "must be a date in YYYY-MM-DD or MM-YYYY format"
+	case "gt":
+		return "must be greater than " + param
+	case "gte":
+		return "must be at least " + param
+	case "lt":
+		return "must be less than " + param
+	case "lte":
+		return "must be at most " + param
+	case "max":
+		if isString {
+			return "must be at most " + param + " characters long"
+		}
+		return "must be at most " + param
+	case "min":
+		if isString {
+			return "must be at least " + param + " characters long"
+		}
+		return "must be at least " + param
+	case "oneof":
+		return "must be one of: " + param
+	case "iso4217":
+		return "must be an ISO 4217 currency code"
+	}
+
+	return "failed on the '" + fe.Tag() + "' rule"
+}
+
+var (
+	amountType = reflect.TypeOf(model.Amount(0))
+	rateType   = reflect.TypeOf(model.Rate(0))
+)
+
+// messageParam возвращает параметр правила; границы денежных сумм и курсов записываются
+// в единицах, а не в минимальных долях
+func messageParam(fe validator.FieldError) string {
+	if fe.Type() != amountType && fe.Type() != rateType {
+		return fe.Param()
+	}
+
+	value, err := strconv.ParseInt(fe.Param(), 10, 64)
+	if err != nil {
+		return fe.Param()
+	}
+
+	if fe.Type() == rateType {
+		return model.Rate(value).String()
+	}
+	return model.Amount(value).String()
+}
@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    ADD COLUMN version INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
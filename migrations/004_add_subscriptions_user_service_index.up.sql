@@ -0,0 +1 @@
+CREATE INDEX idx_subscriptions_user_service ON subscriptions(user_id, service_name, start_date);
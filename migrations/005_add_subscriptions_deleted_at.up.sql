@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_subscriptions_deleted_at ON subscriptions(deleted_at) WHERE deleted_at IS NOT NULL;
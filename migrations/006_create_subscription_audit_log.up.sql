@@ -0,0 +1,12 @@
+CREATE TABLE subscription_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL,
+    action TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    actor TEXT,
+    request_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_subscription_audit_log_subscription_id ON subscription_audit_log(subscription_id, id);
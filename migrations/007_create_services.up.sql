@@ -0,0 +1,51 @@
+CREATE TABLE services (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    category VARCHAR(100),
+    default_price INTEGER CHECK (default_price > 0),
+    currency CHAR(3),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Название и синонимы сервиса; alias_key - написание в нижнем регистре с одиночными пробелами,
+-- по нему названия сопоставляются с сервисом. Строка с названием самого сервиса тоже хранится здесь
+CREATE TABLE service_aliases (
+    alias_key VARCHAR(255) PRIMARY KEY,
+    alias VARCHAR(255) NOT NULL,
+    service_id INTEGER NOT NULL REFERENCES services(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_service_aliases_service_id ON service_aliases(service_id);
+
+-- Существующие названия, отличающиеся только регистром и пробелами, сводятся к одному сервису
+-- с самым частым написанием
+INSERT INTO services (name)
+SELECT DISTINCT ON (alias_key) name
+FROM (
+    SELECT lower(regexp_replace(btrim(service_name), '\s+', ' ', 'g')) AS alias_key,
+           regexp_replace(btrim(service_name), '\s+', ' ', 'g') AS name,
+           count(*) AS uses
+    FROM subscriptions
+    GROUP BY 1, 2
+) names
+ORDER BY alias_key, uses DESC, name;
+
+INSERT INTO service_aliases (alias_key, alias, service_id)
+SELECT lower(name), name, id FROM services;
+
+ALTER TABLE subscriptions ADD COLUMN service_id INTEGER REFERENCES services(id);
+
+UPDATE subscriptions s
+SET service_id = a.service_id,
+    service_name = sv.name,
+    version = s.version + CASE WHEN s.service_name = sv.name THEN 0 ELSE 1 END
+FROM service_aliases a
+JOIN services sv ON sv.id = a.service_id
+WHERE a.alias_key = lower(regexp_replace(btrim(s.service_name), '\s+', ' ', 'g'));
+
+ALTER TABLE subscriptions ALTER COLUMN service_id SET NOT NULL;
+
+DROP INDEX idx_subscriptions_user_service;
+CREATE INDEX idx_subscriptions_user_service ON subscriptions(user_id, service_id, start_date);
+CREATE INDEX idx_subscriptions_service_id ON subscriptions(service_id);
@@ -0,0 +1,9 @@
+-- Изменения цены подписки: price действует с месяца effective_from до следующего изменения.
+-- До первого изменения действует subscriptions.price
+CREATE TABLE subscription_prices (
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    price INTEGER NOT NULL CHECK (price > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (subscription_id, effective_from)
+);
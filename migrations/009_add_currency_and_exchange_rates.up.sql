@@ -0,0 +1,17 @@
+-- Валюта цены подписки (ISO 4217). Существующие подписки получают валюту сервиса из каталога или рубли
+ALTER TABLE subscriptions ADD COLUMN currency CHAR(3);
+
+UPDATE subscriptions s
+SET currency = COALESCE((SELECT currency FROM services WHERE id = s.service_id), 'RUB');
+
+ALTER TABLE subscriptions ALTER COLUMN currency SET NOT NULL;
+
+-- Курсы валют по месяцам: rate - стоимость единицы валюты в рублях.
+-- Курс действует с месяца month до следующего загруженного курса этой валюты
+CREATE TABLE exchange_rates (
+    currency CHAR(3) NOT NULL,
+    month DATE NOT NULL,
+    rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (currency, month)
+);
@@ -0,0 +1,7 @@
+-- Денежные суммы хранятся в минимальных единицах валюты (сотых долях), чтобы представлять
+-- цены вроде 399.90 и не переполняться при суммировании
+ALTER TABLE subscriptions ALTER COLUMN price TYPE BIGINT USING price::bigint * 100;
+
+ALTER TABLE subscription_prices ALTER COLUMN price TYPE BIGINT USING price::bigint * 100;
+
+ALTER TABLE services ALTER COLUMN default_price TYPE BIGINT USING default_price::bigint * 100;
@@ -0,0 +1,5 @@
+-- Даты подписок хранятся с точностью до дня. Раньше дата окончания хранилась первым числом
+-- месяца и означала весь месяц, поэтому переводим ее на последнее число этого месяца
+UPDATE subscriptions
+SET end_date = (date_trunc('month', end_date) + INTERVAL '1 month - 1 day')::date
+WHERE end_date IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Периодичность списаний: цена подписки указывается за один период, billing_interval - количество
+-- месяцев (для weekly - недель) в периоде. Существующие подписки ежемесячные со списанием в день даты начала
+ALTER TABLE subscriptions
+    ADD COLUMN billing_period VARCHAR(16) NOT NULL DEFAULT 'monthly'
+        CHECK (billing_period IN ('weekly', 'monthly', 'quarterly', 'yearly', 'custom')),
+    ADD COLUMN billing_interval SMALLINT NOT NULL DEFAULT 1 CHECK (billing_interval BETWEEN 1 AND 120),
+    ADD COLUMN anchor_day SMALLINT CHECK (anchor_day BETWEEN 1 AND 31);
+
+UPDATE subscriptions SET anchor_day = EXTRACT(DAY FROM start_date);
+
+ALTER TABLE subscriptions ALTER COLUMN anchor_day SET NOT NULL;
@@ -0,0 +1,16 @@
+-- Пробный период: первые trial_days дней подписки не оплачиваются
+ALTER TABLE subscriptions ADD COLUMN trial_days SMALLINT NOT NULL DEFAULT 0 CHECK (trial_days BETWEEN 0 AND 365);
+
+-- Промо-цены: в периоде [start_date, end_date] списания идут по цене акции вместо обычной.
+-- Периоды акций одной подписки не пересекаются
+CREATE TABLE subscription_promotions (
+    id SERIAL PRIMARY KEY,
+    subscription_id INTEGER NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    price BIGINT NOT NULL CHECK (price >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_subscription_promotions_subscription_id ON subscription_promotions (subscription_id, start_date);
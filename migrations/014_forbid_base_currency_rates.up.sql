@@ -0,0 +1,5 @@
+-- Рубль - базовая валюта: курсы задаются в рублях, и курс рубля к самому себе всегда равен 1.
+-- Загруженные ранее курсы рубля при расчетах не использовались
+DELETE FROM exchange_rates WHERE currency = 'RUB';
+
+ALTER TABLE exchange_rates ADD CONSTRAINT exchange_rates_not_base_currency CHECK (currency <> 'RUB');